@@ -1,21 +1,131 @@
 package senml
 
-import "github.com/ugorji/go/codec"
+import (
+	"fmt"
+
+	"github.com/ugorji/go/codec"
+)
 
 var cbor codec.CborHandle
 
-// EncodeCBOR encodes a list of measurements into CBOR.
-func EncodeCBOR(list []Measurement) (b []byte, err error) {
-	err = codec.NewEncoderBytes(&b, &cbor).Encode(Encode(list))
-	return
+// EncodeCBOR encodes a list of measurements into CBOR using the registered "cbor" codec.
+func EncodeCBOR(list []Measurement) ([]byte, error) {
+	return encodeCodec("cbor", list)
 }
 
-// DecodeCBOR decodes a list of measurements from CBOR.
+// DecodeCBOR decodes a list of measurements from CBOR using the registered "cbor" codec.
 func DecodeCBOR(c []byte) ([]Measurement, error) {
-	obj := make([]Record, 0)
+	return decodeCodec("cbor", c)
+}
+
+// EncodeCBORRecords encodes a list of records into CBOR.
+func EncodeCBORRecords(records []Record) (b []byte, err error) {
+	err = codec.NewEncoderBytes(&b, &cbor).Encode(records)
+	return
+}
+
+// DecodeCBORRecords decodes a list of records from CBOR.
+// Records are decoded without CodecDecodeSelf first, which fails on the text string
+// label of object link values, as that is considerably faster.
+func DecodeCBORRecords(c []byte) ([]Record, error) {
+	// Slices are nil, as decoding an indefinite length array into an empty slice fails
+	var records []cborRecord
+	if err := codec.NewDecoderBytes(c, &cbor).Decode(&records); err == nil {
+		obj := make([]Record, len(records))
+		for i, r := range records {
+			obj[i] = Record(r)
+		}
+		return obj, nil
+	}
+
+	var obj []Record
 	err := codec.NewDecoderBytes(c, &cbor).Decode(&obj)
 	if err != nil {
 		return nil, err
 	}
-	return Decode(obj)
+	if obj == nil {
+		obj = make([]Record, 0)
+	}
+	return obj, nil
+}
+
+// cborObjectLinkKey is the CBOR label of the object link value field.
+// This label is a text string, as opposed to the integer labels of the other fields.
+const cborObjectLinkKey = "vlo"
+
+// cborRecord is a Record without its CBOR encoding methods,
+// which is encoded using the struct tags of Record.
+type cborRecord Record
+
+// cborMap is a CBOR map represented by a list of alternating keys and values,
+// which retains the order of the entries.
+type cborMap []interface{}
+
+// MapBySlice marks cborMap as a map for the CBOR codec.
+func (cborMap) MapBySlice() {}
+
+// cborRecordMap returns the CBOR map of a record, including the object link value.
+func cborRecordMap(r *Record) (cborMap, error) {
+	var b []byte
+	if err := codec.NewEncoderBytes(&b, &cbor).Encode((*cborRecord)(r)); err != nil {
+		return nil, err
+	}
+
+	var m cborMap
+	if err := codec.NewDecoderBytes(b, &cbor).Decode(&m); err != nil {
+		return nil, err
+	}
+	if r.ObjectLink != "" {
+		m = append(m, cborObjectLinkKey, r.ObjectLink)
+	}
+	return m, nil
+}
+
+// CodecEncodeSelf encodes the record into CBOR.
+// It implements the Selfer interface of the codec package,
+// and is used for encoding the object link value with its text string label.
+func (r *Record) CodecEncodeSelf(e *codec.Encoder) {
+	if r.ObjectLink == "" {
+		e.MustEncode((*cborRecord)(r))
+		return
+	}
+
+	m, err := cborRecordMap(r)
+	if err != nil {
+		panic(err)
+	}
+	e.MustEncode(m)
+}
+
+// CodecDecodeSelf decodes the record from CBOR.
+// It implements the Selfer interface of the codec package,
+// and is used for decoding the object link value with its text string label.
+// A null object link value results in an empty value.
+func (r *Record) CodecDecodeSelf(d *codec.Decoder) {
+	var m cborMap
+	d.MustDecode(&m)
+
+	fields := make(cborMap, 0, len(m))
+	for i := 0; i+1 < len(m); i += 2 {
+		if k, ok := m[i].(string); !ok || k != cborObjectLinkKey {
+			fields = append(fields, m[i], m[i+1])
+			continue
+		}
+
+		switch v := m[i+1].(type) {
+		case nil:
+		case string:
+			r.ObjectLink = v
+		default:
+			panic(fmt.Errorf("invalid object link: %v", v))
+		}
+	}
+
+	var b []byte
+	if err := codec.NewEncoderBytes(&b, &cbor).Encode(fields); err != nil {
+		panic(err)
+	}
+	if err := codec.NewDecoderBytes(b, &cbor).Decode((*cborRecord)(r)); err != nil {
+		panic(err)
+	}
 }
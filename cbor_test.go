@@ -1,6 +1,11 @@
 package senml
 
-import "testing"
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
 
 func TestEncodeCBORExamples(t *testing.T) {
 	for n, example := range testVectors {
@@ -54,3 +59,38 @@ func BenchmarkDecodeCBOR(b *testing.B) {
 		}
 	}
 }
+
+func TestCBORObjectLink(t *testing.T) {
+	example := testVectors["Object Links"]
+	b, err := EncodeCBOR(example.Result)
+	if err != nil {
+		t.Fatalf("Error encoding: %s", err)
+	}
+	if !bytes.Equal(b, example.CBOR) {
+		t.Errorf("Encoding incorrect, got:\n%x\nexpected:\n%x", b, example.CBOR)
+	}
+
+	// Null and invalid object link values
+	for c, ok := range map[string]bool{
+		"81a2006161 63766c6ff6":   true,
+		"81a2006161 63766c6f01":   false,
+		"81a2006161 63766c6f6133": true,
+		"81a2006161 63766c6f":     false,
+		"9fbf006161 ffff":         true,
+	} {
+		b, _ := hex.DecodeString(strings.Replace(c, " ", "", -1))
+		_, err := DecodeCBORRecords(b)
+		if ok && err != nil {
+			t.Errorf("Error decoding %s: %s", c, err)
+		} else if !ok && err == nil {
+			t.Errorf("Expected error decoding %s", c)
+		}
+	}
+
+	// Indefinite length pack and record
+	b, _ = hex.DecodeString("9fbf00616163766c6f6133ffff")
+	records, err := DecodeCBORRecords(b)
+	if err != nil || len(records) != 1 || records[0].Name != "a" || records[0].ObjectLink != "3" {
+		t.Errorf("Decoding of indefinite length object link incorrect, got %#v (%v)", records, err)
+	}
+}
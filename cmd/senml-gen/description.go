@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+
+	"github.com/silkeh/senml"
+	"gopkg.in/yaml.v3"
+)
+
+// Measurement kinds supported in a device description.
+const (
+	kindValue   = "value"
+	kindSum     = "sum"
+	kindString  = "string"
+	kindBoolean = "boolean"
+	kindData    = "data"
+)
+
+// Description represents a description of a set of devices.
+type Description struct {
+	Package string   `yaml:"package" json:"package"`
+	Devices []Device `yaml:"devices" json:"devices"`
+}
+
+// Device represents the description of a single device model.
+type Device struct {
+	Type         string        `yaml:"type" json:"type"`
+	Description  string        `yaml:"description" json:"description"`
+	Measurements []Measurement `yaml:"measurements" json:"measurements"`
+}
+
+// Measurement represents the description of a single measurement of a device.
+type Measurement struct {
+	Name        string   `yaml:"name" json:"name"`
+	Field       string   `yaml:"field" json:"field"`
+	Description string   `yaml:"description" json:"description"`
+	Unit        string   `yaml:"unit" json:"unit"`
+	Kind        string   `yaml:"kind" json:"kind"`
+	Min         *float64 `yaml:"min" json:"min"`
+	Max         *float64 `yaml:"max" json:"max"`
+}
+
+// GoType returns the Go type of the measurement.
+func (m *Measurement) GoType() string {
+	switch m.Kind {
+	case kindString:
+		return "string"
+	case kindBoolean:
+		return "bool"
+	case kindData:
+		return "[]byte"
+	default:
+		return "float64"
+	}
+}
+
+// Type returns the name of the senml measurement type.
+func (m *Measurement) Type() string {
+	switch m.Kind {
+	case kindSum:
+		return "Sum"
+	case kindString:
+		return "String"
+	case kindBoolean:
+		return "Boolean"
+	case kindData:
+		return "Data"
+	default:
+		return "Value"
+	}
+}
+
+// Numeric returns true if the measurement has a numeric value.
+func (m *Measurement) Numeric() bool {
+	return m.Kind == kindValue || m.Kind == kindSum
+}
+
+// ParseDescription parses a device description in YAML or JSON.
+func ParseDescription(b []byte) (*Description, error) {
+	d := new(Description)
+	if err := yaml.Unmarshal(b, d); err != nil {
+		return nil, err
+	}
+	return d, d.validate()
+}
+
+// validate validates the description and sets defaults.
+func (d *Description) validate() error {
+	if d.Package == "" {
+		d.Package = "devices"
+	}
+	if !token.IsIdentifier(d.Package) {
+		return fmt.Errorf("invalid package name %q", d.Package)
+	}
+
+	if len(d.Devices) == 0 {
+		return fmt.Errorf("no devices")
+	}
+
+	types := make(map[string]bool)
+	for i := range d.Devices {
+		dev := &d.Devices[i]
+		if !token.IsIdentifier(dev.Type) || !token.IsExported(dev.Type) {
+			return fmt.Errorf("invalid device type %q", dev.Type)
+		}
+		if types[dev.Type] {
+			return fmt.Errorf("duplicate device type %q", dev.Type)
+		}
+		types[dev.Type] = true
+
+		if err := dev.validate(); err != nil {
+			return fmt.Errorf("device %s: %w", dev.Type, err)
+		}
+	}
+
+	return nil
+}
+
+// validate validates the device description and sets defaults.
+func (dev *Device) validate() error {
+	names := make(map[string]bool)
+	fields := make(map[string]bool)
+	for i := range dev.Measurements {
+		m := &dev.Measurements[i]
+		if m.Name == "" {
+			return fmt.Errorf("measurement %v has no name", i)
+		}
+		if strings.ContainsAny(m.Name, "\"`,\\") || strings.IndexFunc(m.Name, unicode.IsControl) >= 0 {
+			return fmt.Errorf("invalid character in name of measurement %q", m.Name)
+		}
+		if names[m.Name] {
+			return fmt.Errorf("duplicate measurement %q", m.Name)
+		}
+		names[m.Name] = true
+
+		if m.Field == "" {
+			m.Field = fieldName(m.Name)
+		}
+		if !token.IsIdentifier(m.Field) || !token.IsExported(m.Field) {
+			return fmt.Errorf("invalid field name %q for measurement %q", m.Field, m.Name)
+		}
+		if fields[m.Field] {
+			return fmt.Errorf("duplicate field %q", m.Field)
+		}
+		fields[m.Field] = true
+
+		switch m.Kind {
+		case "":
+			m.Kind = kindValue
+		case kindValue, kindSum, kindString, kindBoolean, kindData:
+		default:
+			return fmt.Errorf("invalid kind %q for measurement %q", m.Kind, m.Name)
+		}
+
+		if !senml.Unit(m.Unit).Known() {
+			return fmt.Errorf("unknown unit %q for measurement %q", m.Unit, m.Name)
+		}
+		if (m.Min != nil || m.Max != nil) && !m.Numeric() {
+			return fmt.Errorf("range set for non-numeric measurement %q", m.Name)
+		}
+		if m.Min != nil && m.Max != nil && *m.Min > *m.Max {
+			return fmt.Errorf("invalid range for measurement %q", m.Name)
+		}
+	}
+
+	return nil
+}
+
+// fieldName returns the Go field name for a measurement name, eg: "battery-level" results in "BatteryLevel".
+func fieldName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || (unicode.IsDigit(r) && b.Len() > 0):
+			if upper {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"strconv"
+	"text/template"
+)
+
+// codeTemplate is the template for the generated code.
+var codeTemplate = template.Must(template.New("code").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+	"unit": func(u string) string {
+		if u == "" {
+			return "senml.None"
+		}
+		return "senml.Unit(" + strconv.Quote(u) + ")"
+	},
+	"float": func(f *float64) string { return strconv.FormatFloat(*f, 'g', -1, 64) },
+}).Parse(`// Code generated by senml-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/silkeh/senml"
+)
+{{range .Devices}}{{$dev := .}}
+{{if .Description}}// {{.Type}} {{.Description}}{{else}}// {{.Type}} represents the measurements of a {{.Type}} device.{{end}}
+type {{.Type}} struct {
+{{- range .Measurements}}
+	{{if .Description}}// {{.Field}} {{.Description}}
+	{{end}}{{.Field}} {{.GoType}} ` + "`" + `senml:"{{.Name}}{{if .Unit}},unit={{.Unit}}{{end}}{{if eq .Kind "sum"}},kind=sum{{end}}"` + "`" + `
+{{- end}}
+}
+
+// Measurements returns the measurements of the {{.Type}} with the given base name and time.
+func (d *{{.Type}}) Measurements(baseName string, t time.Time) []senml.Measurement {
+	return []senml.Measurement{
+{{- range .Measurements}}
+		senml.New{{.Type}}(baseName+{{quote .Name}}, d.{{.Field}}, {{unit .Unit}}, t, 0),
+{{- end}}
+	}
+}
+
+// FromMeasurements sets the values of the {{.Type}} from measurements with the given base name.
+// Numeric values are converted to the unit of the field.
+// Measurements with other names are ignored.
+// The resulting values are validated.
+func (d *{{.Type}}) FromMeasurements(list []senml.Measurement, baseName string) error {
+	for _, m := range list {
+		switch m.Attrs().Name {
+{{- range .Measurements}}
+		case baseName + {{quote .Name}}:
+			v, ok := m.(*senml.{{.Type}})
+			if !ok {
+				return fmt.Errorf("invalid measurement type %T for %q", m, m.Attrs().Name)
+			}
+{{- if and .Numeric .Unit}}
+			f := v.Value
+			if v.Unit != senml.None && v.Unit != {{unit .Unit}} {
+				var err error
+				if f, err = v.Unit.Convert(f, {{unit .Unit}}); err != nil {
+					return fmt.Errorf("invalid unit for %q: %w", m.Attrs().Name, err)
+				}
+			}
+			d.{{.Field}} = f
+{{- else}}
+			d.{{.Field}} = v.Value
+{{- end}}
+{{- end}}
+		}
+	}
+
+	return d.Validate()
+}
+
+// Validate returns an error if any value of the {{.Type}} is out of range.
+func (d *{{.Type}}) Validate() error {
+{{- range .Measurements}}
+{{- if .Min}}
+	if d.{{.Field}} < {{float .Min}} {
+		return fmt.Errorf("{{.Name}} out of range: %v < {{float .Min}}", d.{{.Field}})
+	}
+{{- end}}
+{{- if .Max}}
+	if d.{{.Field}} > {{float .Max}} {
+		return fmt.Errorf("{{.Name}} out of range: %v > {{float .Max}}", d.{{.Field}})
+	}
+{{- end}}
+{{- end}}
+	return nil
+}
+{{end}}`))
+
+// Generate returns the formatted Go code for a description.
+func Generate(d *Description) ([]byte, error) {
+	var b bytes.Buffer
+	if err := codeTemplate.Execute(&b, d); err != nil {
+		return nil, err
+	}
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerate(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/devices.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := ParseDescription(b)
+	if err != nil {
+		t.Fatalf("Error parsing description: %s", err)
+	}
+
+	code, err := Generate(d)
+	if err != nil {
+		t.Fatalf("Error generating code: %s", err)
+	}
+
+	if *update {
+		if err := ioutil.WriteFile("testdata/devices.go.golden", code, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exp, err := ioutil.ReadFile("testdata/devices.go.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(code, exp) {
+		t.Errorf("Generated code incorrect, got:\n%s", code)
+	}
+
+	if err := typeCheck(code); err != nil {
+		t.Errorf("Generated code does not compile: %s", err)
+	}
+}
+
+// typeCheck parses and type-checks generated code.
+func typeCheck(code []byte) error {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "devices.go", code, 0)
+	if err != nil {
+		return err
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check(f.Name.Name, fset, []*ast.File{f}, nil)
+	return err
+}
+
+func TestParseDescriptionJSON(t *testing.T) {
+	d, err := ParseDescription([]byte(`{
+		"devices": [{
+			"type": "Sensor",
+			"measurements": [{"name": "battery-level", "unit": "%EL", "min": 0, "max": 100}]
+		}]
+	}`))
+	if err != nil {
+		t.Fatalf("Error parsing description: %s", err)
+	}
+
+	m := d.Devices[0].Measurements[0]
+	if d.Package != "devices" || m.Field != "BatteryLevel" || m.Kind != kindValue || *m.Max != 100 {
+		t.Errorf("Description incorrect: %#v", d)
+	}
+}
+
+func TestParseDescriptionErrors(t *testing.T) {
+	tests := map[string]string{
+		"No devices":       `package: devices`,
+		"Invalid package":  `{"package": "my-devices", "devices": [{"type": "A"}]}`,
+		"Invalid type":     `{"devices": [{"type": "sensor"}]}`,
+		"Duplicate type":   `{"devices": [{"type": "A"}, {"type": "A"}]}`,
+		"No name":          `{"devices": [{"type": "A", "measurements": [{"unit": "Cel"}]}]}`,
+		"Duplicate name":   `{"devices": [{"type": "A", "measurements": [{"name": "a"}, {"name": "a"}]}]}`,
+		"Quote in name":    `{"devices": [{"type": "A", "measurements": [{"name": "a\"b"}]}]}`,
+		"Comma in name":    `{"devices": [{"type": "A", "measurements": [{"name": "a,kind=sum"}]}]}`,
+		"Backtick in name": "{\"devices\": [{\"type\": \"A\", \"measurements\": [{\"name\": \"a`b\"}]}]}",
+		"Duplicate field":  `{"devices": [{"type": "A", "measurements": [{"name": "a-b"}, {"name": "a_b"}]}]}`,
+		"Invalid kind":     `{"devices": [{"type": "A", "measurements": [{"name": "a", "kind": "float"}]}]}`,
+		"Unknown unit":     `{"devices": [{"type": "A", "measurements": [{"name": "a", "unit": "cel"}]}]}`,
+		"Invalid range":    `{"devices": [{"type": "A", "measurements": [{"name": "a", "min": 2, "max": 1}]}]}`,
+		"String range":     `{"devices": [{"type": "A", "measurements": [{"name": "a", "kind": "string", "max": 1}]}]}`,
+	}
+
+	for n, test := range tests {
+		if _, err := ParseDescription([]byte(test)); err == nil {
+			t.Errorf("Expected error for %s", n)
+		}
+	}
+}
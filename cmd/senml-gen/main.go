@@ -0,0 +1,81 @@
+// Command senml-gen generates Go types for devices from a device description.
+//
+// The description is a YAML or JSON document listing device types and their measurements:
+//
+//	package: devices
+//	devices:
+//	  - type: Thermostat
+//	    measurements:
+//	      - name: temperature
+//	        unit: Cel
+//	        min: -40
+//	        max: 85
+//	      - name: energy
+//	        unit: kWh
+//	        kind: sum
+//
+// Supported kinds are value (default), sum, string, boolean and data.
+// For every device a struct is generated with Measurements, FromMeasurements and Validate methods.
+//
+// Usage:
+//
+//	senml-gen [-o output.go] description.yaml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"os"
+)
+
+func main() {
+	output := flag.String("o", "", "output file (default stdout)")
+	pkg := flag.String("package", "", "package name, overriding the name in the description")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] description\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *output, *pkg); err != nil {
+		fmt.Fprintf(os.Stderr, "senml-gen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the code for the description in the input file.
+func run(input, output, pkg string) error {
+	b, err := ioutil.ReadFile(input)
+	if err != nil {
+		return err
+	}
+
+	d, err := ParseDescription(b)
+	if err != nil {
+		return err
+	}
+	if pkg != "" {
+		if !token.IsIdentifier(pkg) {
+			return fmt.Errorf("invalid package name %q", pkg)
+		}
+		d.Package = pkg
+	}
+
+	code, err := Generate(d)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return ioutil.WriteFile(output, code, 0644)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/silkeh/senml"
+)
+
+// runConvert runs the convert command.
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	from := fs.String("from", autoFormat, "input format ("+autoFormat+", "+formatNames()+")")
+	to := fs.String("to", "json", "output format ("+formatNames()+")")
+	optimize := fs.Bool("optimize", false, "recalculate the base fields of the pack")
+	output := fs.String("o", "", "output file (default stdout)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: senml convert [flags] [input]\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	in, err := readInput(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	out, err := convert(in, *from, *to, *optimize)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*output, out)
+}
+
+// convert converts a pack between formats.
+// The records are copied as-is, unless optimize is set. In that case the
+// records are resolved and encoded again, recalculating the base fields.
+func convert(in []byte, from, to string, optimize bool) ([]byte, error) {
+	f, err := lookupFormat(to)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := decodeRecords(in, from)
+	if err != nil {
+		return nil, err
+	}
+
+	if optimize {
+		autoTime := senml.AutoTime
+		senml.AutoTime = false
+		defer func() { senml.AutoTime = autoTime }()
+
+		list, err := senml.Decode(records)
+		if err != nil {
+			return nil, err
+		}
+		records = senml.Encode(list)
+	}
+
+	return f.Encode(records)
+}
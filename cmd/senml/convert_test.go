@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/silkeh/senml"
+)
+
+const testJSON = `[{"bn":"sensor:","bt":1555487588,"bu":"Cel","n":"temperature","v":23.5},{"n":"humidity","u":"%RH","v":33.7}]`
+
+func TestConvert(t *testing.T) {
+	for _, f := range []string{"json", "cbor", "xml"} {
+		b, err := convert([]byte(testJSON), autoFormat, f, false)
+		if err != nil {
+			t.Errorf("Error converting to %s: %s", f, err)
+			continue
+		}
+
+		r, err := convert(b, autoFormat, "application/senml+json", false)
+		if err != nil {
+			t.Errorf("Error converting from %s: %s", f, err)
+			continue
+		}
+		if string(r) != testJSON {
+			t.Errorf("Conversion from %s incorrect, got:\n%s\nexpected:\n%s", f, r, testJSON)
+		}
+	}
+}
+
+func TestConvertOptimize(t *testing.T) {
+	in := `[
+		{"n":"sensor:temperature","u":"Cel","t":1555487588,"v":23.5},
+		{"n":"sensor:humidity","u":"%RH","t":1555487588,"v":33.7}
+	]`
+
+	senml.AutoTime = true
+	b, err := convert([]byte(in), "json", "json", true)
+	if err != nil {
+		t.Fatalf("Error converting: %s", err)
+	}
+	if !senml.AutoTime {
+		t.Errorf("AutoTime was not restored")
+	}
+
+	exp := `[{"bn":"sensor:","bt":1555487588,"bu":"Cel","n":"temperature","v":23.5},{"n":"humidity","u":"%RH","v":33.7}]`
+	if !bytes.Equal(b, []byte(exp)) {
+		t.Errorf("Optimized conversion incorrect, got:\n%s\nexpected:\n%s", b, exp)
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := map[string]struct {
+		In, From, To string
+	}{
+		"Empty input":   {"", autoFormat, "json"},
+		"Unknown input": {"foo", autoFormat, "json"},
+		"Unknown from":  {testJSON, "yaml", "json"},
+		"Unknown to":    {testJSON, "json", "yaml"},
+		"Invalid input": {testJSON, "xml", "json"},
+	}
+
+	for n, test := range tests {
+		if _, err := convert([]byte(test.In), test.From, test.To, false); err == nil {
+			t.Errorf("Expected error for %s", n)
+		}
+	}
+}
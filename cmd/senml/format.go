@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/silkeh/senml"
+)
+
+// autoFormat is the name of the automatically detected input format.
+const autoFormat = "auto"
+
+// formatNames returns the names of the supported formats.
+func formatNames() string {
+	return strings.Join(senml.Codecs(), ", ")
+}
+
+// lookupFormat returns the codec for a format name or media type.
+func lookupFormat(name string) (senml.Codec, error) {
+	if c, ok := senml.LookupCodec(strings.ToLower(name)); ok {
+		return c, nil
+	}
+	if c, ok := senml.LookupMediaType(name); ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q, supported: %s", name, formatNames())
+}
+
+// decodeRecords decodes records in the given format, detecting the format if needed.
+func decodeRecords(b []byte, name string) ([]senml.Record, error) {
+	if name == autoFormat {
+		var err error
+		if name, err = senml.Detect(b); err != nil {
+			return nil, fmt.Errorf("unable to detect input format: %w", err)
+		}
+	}
+
+	c, err := lookupFormat(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.Decode(b)
+}
+
+// readInput reads the input from a file, or from stdin if the path is empty or "-".
+func readInput(path string) ([]byte, error) {
+	if path == "" || path == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(path)
+}
+
+// writeOutput writes the output to a file, or to stdout if the path is empty or "-".
+func writeOutput(path string, b []byte) error {
+	if path == "" || path == "-" {
+		_, err := os.Stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
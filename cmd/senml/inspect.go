@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/silkeh/senml"
+)
+
+// runInspect runs the inspect command.
+func runInspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	from := fs.String("from", autoFormat, "input format ("+autoFormat+", "+formatNames()+")")
+	asJSON := fs.Bool("json", false, "print the measurements as JSON instead of a table")
+	output := fs.String("o", "", "output file (default stdout)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: senml inspect [flags] [input]\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	in, err := readInput(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	out, err := inspect(in, *from, *asJSON)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*output, out)
+}
+
+// inspectedMeasurement represents a resolved measurement in the JSON output of inspect.
+type inspectedMeasurement struct {
+	Name       string      `json:"name"`
+	Type       string      `json:"type"`
+	Unit       string      `json:"unit,omitempty"`
+	Time       time.Time   `json:"time"`
+	UpdateTime float64     `json:"updateTime,omitempty"`
+	Value      interface{} `json:"value"`
+}
+
+// inspect returns the resolved measurements of a pack as a table or as JSON.
+func inspect(in []byte, from string, asJSON bool) ([]byte, error) {
+	records, err := decodeRecords(in, from)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := senml.Decode(records)
+	if err != nil {
+		return nil, err
+	}
+
+	if asJSON {
+		ms := make([]inspectedMeasurement, len(list))
+		for i, m := range list {
+			a := m.Attrs()
+			ms[i] = inspectedMeasurement{
+				Name:       a.Name,
+				Type:       measurementType(m),
+				Unit:       string(a.Unit),
+				Time:       a.Time.UTC(),
+				UpdateTime: a.UpdateTime.Seconds(),
+				Value:      measurementValue(m),
+			}
+		}
+		b, err := json.MarshalIndent(ms, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tVALUE\tUNIT\tTIME")
+	for _, m := range list {
+		a := m.Attrs()
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.Name, measurementType(m),
+			formatValue(m), a.Unit, a.Time.UTC().Format(time.RFC3339Nano))
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// measurementType returns the type of a measurement.
+func measurementType(m senml.Measurement) string {
+	switch m.(type) {
+	case *senml.Value:
+		return "value"
+	case *senml.Sum:
+		return "sum"
+	case *senml.String:
+		return "string"
+	case *senml.Boolean:
+		return "boolean"
+	case *senml.Data:
+		return "data"
+	case *senml.ObjectLink:
+		return "objectlink"
+	default:
+		return "unknown"
+	}
+}
+
+// measurementValue returns the value of a measurement.
+func measurementValue(m senml.Measurement) interface{} {
+	switch v := m.(type) {
+	case *senml.Value:
+		return v.Value
+	case *senml.Sum:
+		return v.Value
+	case *senml.String:
+		return v.Value
+	case *senml.Boolean:
+		return v.Value
+	case *senml.Data:
+		return v.Value
+	case *senml.ObjectLink:
+		return v.Value
+	default:
+		return nil
+	}
+}
+
+// formatValue returns the value of a measurement as a string.
+func formatValue(m senml.Measurement) string {
+	switch v := measurementValue(m).(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return strconv.Quote(v)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
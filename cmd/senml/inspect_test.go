@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestInspect(t *testing.T) {
+	in := `[{"bn":"sensor:","bt":1555487588,"bu":"Cel","n":"temperature","v":23.5},{"n":"state","vs":"ok","t":1}]`
+	tests := map[bool]string{
+		false: "NAME                TYPE    VALUE  UNIT  TIME\n" +
+			"sensor:temperature  value   23.5   Cel   2019-04-17T07:53:08Z\n" +
+			"sensor:state        string  \"ok\"   Cel   2019-04-17T07:53:09Z\n",
+		true: `[
+  {
+    "name": "sensor:temperature",
+    "type": "value",
+    "unit": "Cel",
+    "time": "2019-04-17T07:53:08Z",
+    "value": 23.5
+  },
+  {
+    "name": "sensor:state",
+    "type": "string",
+    "unit": "Cel",
+    "time": "2019-04-17T07:53:09Z",
+    "value": "ok"
+  }
+]
+`,
+	}
+
+	for asJSON, exp := range tests {
+		b, err := inspect([]byte(in), autoFormat, asJSON)
+		if err != nil {
+			t.Errorf("Error inspecting (JSON: %v): %s", asJSON, err)
+			continue
+		}
+		if string(b) != exp {
+			t.Errorf("Inspect output (JSON: %v) incorrect, got:\n%s\nexpected:\n%s", asJSON, b, exp)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Command senml converts, inspects and validates SenML packs.
+//
+// Usage:
+//
+//	senml <command> [flags] [input]
+//
+// The commands are:
+//
+//	convert   convert a pack to another format
+//	inspect   print the resolved measurements of a pack
+//	validate  check a pack against the rules of RFC 8428
+//
+// Input is read from the given file, or from stdin if no file is given.
+// Run "senml <command> -h" for the flags of a command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// command represents a subcommand.
+type command struct {
+	Name  string
+	Usage string
+	Run   func(args []string) error
+}
+
+// commands contains the supported subcommands.
+var commands = []command{
+	{"convert", "convert a pack to another format", runConvert},
+	{"inspect", "print the resolved measurements of a pack", runInspect},
+	{"validate", "check a pack against the rules of RFC 8428", runValidate},
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.Name == flag.Arg(0) {
+			if err := c.Run(flag.Args()[1:]); err != nil {
+				if err == flag.ErrHelp {
+					os.Exit(2)
+				}
+				fmt.Fprintf(os.Stderr, "senml %s: %s\n", c.Name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "senml: unknown command %q\n", flag.Arg(0))
+	usage()
+	os.Exit(2)
+}
+
+// usage prints the usage of the command.
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: senml <command> [flags] [input]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.Name, c.Usage)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/silkeh/senml"
+)
+
+// runValidate runs the validate command.
+func runValidate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	from := fs.String("from", autoFormat, "input format ("+autoFormat+", "+formatNames()+")")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: senml validate [flags] [input]\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	in, err := readInput(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return validate(os.Stdout, in, *from)
+}
+
+// validate checks a pack, writing an error message for each invalid record.
+// An error is returned if the pack is invalid.
+func validate(w io.Writer, in []byte, from string) error {
+	records, err := decodeRecords(in, from)
+	if err != nil {
+		return err
+	}
+
+	err = senml.Validate(records)
+	if err == nil {
+		_, err = senml.Decode(records)
+	}
+
+	var verr senml.ValidationError
+	if errors.As(err, &verr) {
+		for _, e := range verr {
+			fmt.Fprintln(w, e)
+		}
+		return fmt.Errorf("%d of %d records invalid", invalidRecords(verr), len(records))
+	}
+
+	return err
+}
+
+// invalidRecords returns the number of records with errors.
+func invalidRecords(errs senml.ValidationError) int {
+	records := make(map[int]bool)
+	for _, e := range errs {
+		records[e.Record] = true
+	}
+	return len(records)
+}
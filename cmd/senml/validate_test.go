@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		In, Output string
+		Valid      bool
+	}{
+		"Valid": {
+			In:    `[{"bn":"sensor:","n":"temperature","u":"Cel","v":23.5}]`,
+			Valid: true,
+		},
+		"Invalid": {
+			In:     `[{"bn":"sensor:","n":"temperature","u":"Cel","v":23.5},{"n":"humidity","u":"foo"}]`,
+			Output: "record 1: record contains no value or sum\nrecord 1: unknown unit \"foo\"\n",
+		},
+		"Malformed": {
+			In: `[{"n":1}]`,
+		},
+	}
+
+	for n, test := range tests {
+		var buf bytes.Buffer
+		err := validate(&buf, []byte(test.In), autoFormat)
+		if (err == nil) != test.Valid {
+			t.Errorf("Validation result for %s incorrect, got error: %v", n, err)
+		}
+		if buf.String() != test.Output {
+			t.Errorf("Validation output for %s incorrect, got:\n%s\nexpected:\n%s", n, buf.String(), test.Output)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package coap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/plgd-dev/go-coap/v2/message"
+	"github.com/plgd-dev/go-coap/v2/message/codes"
+	"github.com/plgd-dev/go-coap/v2/mux"
+	"github.com/plgd-dev/go-coap/v2/udp"
+	"github.com/plgd-dev/go-coap/v2/udp/client"
+	"github.com/silkeh/senml"
+)
+
+// ResponseError is returned when a server responds with an unexpected response code.
+type ResponseError struct {
+	Code    codes.Code
+	Message string
+}
+
+// Error returns the response code and diagnostic payload.
+func (e *ResponseError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("unexpected response code %v", e.Code)
+	}
+	return fmt.Sprintf("unexpected response code %v: %s", e.Code, e.Message)
+}
+
+// responseError returns a ResponseError for a response.
+func responseError(m *message.Message) error {
+	b, _ := readBody(m.Body)
+	return &ResponseError{Code: m.Code, Message: string(b)}
+}
+
+// Observation represents an active observation of a resource.
+type Observation = mux.Observation
+
+// Client requests and sends SenML packs using CoAP.
+type Client struct {
+	conn mux.Client
+
+	// ContentFormat is the Content-Format used for requests and responses.
+	ContentFormat senml.ContentFormat
+}
+
+// NewClient returns a new Client using the given connection.
+func NewClient(conn mux.Client) *Client {
+	return &Client{conn: conn, ContentFormat: DefaultContentFormat}
+}
+
+// Dial returns a new Client connected to the given address using UDP.
+func Dial(addr string) (*Client, error) {
+	cc, err := udp.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(client.NewClient(cc)), nil
+}
+
+// Close closes the connection of the client.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Get returns the measurements of the resource at the given path.
+func (c *Client) Get(ctx context.Context, path string) ([]senml.Measurement, error) {
+	resp, err := c.conn.Get(ctx, path, uintOption(message.Accept, uint32(c.ContentFormat)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != codes.Content {
+		return nil, responseError(resp)
+	}
+	return decode(resp)
+}
+
+// Post sends measurements to the resource at the given path.
+func (c *Client) Post(ctx context.Context, path string, list []senml.Measurement) error {
+	b, err := encode(c.ContentFormat, list)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.conn.Post(ctx, path, message.MediaType(c.ContentFormat), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	switch resp.Code {
+	case codes.Created, codes.Changed, codes.Content:
+		return nil
+	default:
+		return responseError(resp)
+	}
+}
+
+// Observe registers an observation of the resource at the given path.
+// The given function is called with the current measurements, and with the
+// measurements in every following notification.
+// Notifications that cannot be decoded result in a call with an error.
+func (c *Client) Observe(ctx context.Context, path string, fn func([]senml.Measurement, error)) (Observation, error) {
+	return c.conn.Observe(ctx, path, func(n *message.Message) {
+		if n.Code != codes.Content {
+			fn(nil, responseError(n))
+			return
+		}
+		fn(decode(n))
+	}, uintOption(message.Accept, uint32(c.ContentFormat)))
+}
@@ -0,0 +1,94 @@
+// Package coap provides CoAP resources, handlers and a client for SenML packs.
+// Resources can be discovered using the CoRE Link Format, see Links.
+// It is built on the github.com/plgd-dev/go-coap/v2 package.
+package coap
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+
+	"github.com/plgd-dev/go-coap/v2/message"
+	"github.com/plgd-dev/go-coap/v2/message/codes"
+	"github.com/plgd-dev/go-coap/v2/mux"
+	"github.com/silkeh/senml"
+)
+
+// DefaultContentFormat is the Content-Format used when no Accept option is given.
+const DefaultContentFormat = senml.ContentFormatJSON
+
+// lookupCodec returns the registered codec for a Content-Format.
+func lookupCodec(cf senml.ContentFormat) (senml.Codec, bool) {
+	for _, n := range senml.Codecs() {
+		if c, ok := senml.LookupCodec(n); ok && c.ContentFormat() == cf {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// uintOption returns a CoAP option with an unsigned integer value.
+func uintOption(id message.OptionID, v uint32) message.Option {
+	buf := make([]byte, 4)
+	n, _ := message.EncodeUint32(buf, v)
+	return message.Option{ID: id, Value: buf[:n]}
+}
+
+// encode encodes a list of measurements in the given Content-Format.
+func encode(cf senml.ContentFormat, list []senml.Measurement) ([]byte, error) {
+	c, ok := lookupCodec(cf)
+	if !ok {
+		return nil, fmt.Errorf("unsupported Content-Format %v", cf)
+	}
+	return c.Encode(senml.Encode(list))
+}
+
+// decode decodes the measurements in the body of a message.
+func decode(m *message.Message) ([]senml.Measurement, error) {
+	cf, err := m.Options.ContentFormat()
+	if err != nil {
+		return nil, fmt.Errorf("missing Content-Format: %w", err)
+	}
+
+	c, ok := lookupCodec(senml.ContentFormat(cf))
+	if !ok {
+		return nil, fmt.Errorf("unsupported Content-Format %v", cf)
+	}
+
+	b, err := readBody(m.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := c.Decode(b)
+	if err != nil {
+		return nil, err
+	}
+	return senml.Decode(records)
+}
+
+// readBody reads the body of a message.
+func readBody(body io.ReadSeeker) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	return ioutil.ReadAll(body)
+}
+
+// setError sets an error response with a diagnostic payload.
+func setError(w mux.ResponseWriter, code codes.Code, err error) {
+	w.SetResponse(code, message.TextPlain, bytes.NewReader([]byte(err.Error())))
+}
+
+// setInternalError logs an error to the given logger, or the standard logger if it is nil,
+// and sets an Internal Server Error response without exposing the error to the client.
+func setInternalError(w mux.ResponseWriter, err error, logger *log.Logger) {
+	if logger != nil {
+		logger.Printf("senml/coap: internal server error: %s", err)
+	} else {
+		log.Printf("senml/coap: internal server error: %s", err)
+	}
+	w.SetResponse(codes.InternalServerError, message.TextPlain, bytes.NewReader([]byte("Internal Server Error")))
+}
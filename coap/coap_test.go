@@ -0,0 +1,189 @@
+package coap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/plgd-dev/go-coap/v2/message/codes"
+	"github.com/plgd-dev/go-coap/v2/mux"
+	coapNet "github.com/plgd-dev/go-coap/v2/net"
+	"github.com/plgd-dev/go-coap/v2/udp"
+	"github.com/silkeh/senml"
+	"github.com/silkeh/senml/internal/senmltest"
+)
+
+var testMeasurements = senmltest.Measurements()
+
+// testServer runs a CoAP server on the UDP loopback interface.
+func testServer(t *testing.T, router *mux.Router) (addr string, stop func()) {
+	l, err := coapNet.NewListenUDP("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err)
+	}
+
+	s := udp.NewServer(udp.WithMux(router))
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.Serve(l)
+	}()
+
+	return l.LocalAddr().String(), func() {
+		s.Stop()
+		wg.Wait()
+		l.Close()
+	}
+}
+
+func testClient(t *testing.T, addr string) *Client {
+	c, err := Dial(addr)
+	if err != nil {
+		t.Fatalf("Error dialing: %s", err)
+	}
+	return c
+}
+
+func testContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 5*time.Second)
+}
+
+func TestResource(t *testing.T) {
+	senml.AutoTime = false
+	router := mux.NewRouter()
+	router.Handle("/sensor", NewResource(testMeasurements))
+	addr, stop := testServer(t, router)
+	defer stop()
+
+	c := testClient(t, addr)
+	defer c.Close()
+
+	for _, cf := range []senml.ContentFormat{senml.ContentFormatJSON, senml.ContentFormatCBOR, senml.ContentFormatXML} {
+		c.ContentFormat = cf
+		ctx, cancel := testContext()
+		list, err := c.Get(ctx, "/sensor")
+		cancel()
+		if err != nil {
+			t.Errorf("Error getting resource as %v: %s", cf, err)
+			continue
+		}
+		if !senmltest.Equal(list, testMeasurements) {
+			t.Errorf("Resource as %v incorrect, got:\n%v\nexpected:\n%v", cf, list, testMeasurements)
+		}
+	}
+
+	c.ContentFormat = senml.ContentFormatEXI
+	ctx, cancel := testContext()
+	defer cancel()
+	var rerr *ResponseError
+	if _, err := c.Get(ctx, "/sensor"); !errors.As(err, &rerr) || rerr.Code != codes.NotAcceptable {
+		t.Errorf("Expected Not Acceptable, got: %v", err)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	senml.AutoTime = false
+	res := NewResource(testMeasurements)
+	router := mux.NewRouter()
+	router.Handle("/sensor", res)
+	addr, stop := testServer(t, router)
+	defer stop()
+
+	c := testClient(t, addr)
+	defer c.Close()
+	c.ContentFormat = senml.ContentFormatCBOR
+
+	notifications := make(chan []senml.Measurement, 10)
+	ctx, cancel := testContext()
+	defer cancel()
+	obs, err := c.Observe(ctx, "/sensor", func(list []senml.Measurement, err error) {
+		if err != nil {
+			t.Errorf("Error in notification: %s", err)
+		}
+		notifications <- list
+	})
+	if err != nil {
+		t.Fatalf("Error observing: %s", err)
+	}
+
+	updated := []senml.Measurement{
+		senml.NewValue("sensor:temperature", 24, senml.Celsius, time.Unix(1555487598, 0), 0),
+	}
+	for i, exp := range [][]senml.Measurement{testMeasurements, updated} {
+		if i > 0 {
+			res.Set(exp)
+		}
+		select {
+		case list := <-notifications:
+			if !senmltest.Equal(list, exp) {
+				t.Errorf("Notification %v incorrect, got:\n%v\nexpected:\n%v", i, list, exp)
+			}
+		case <-ctx.Done():
+			t.Fatalf("No notification %v received", i)
+		}
+	}
+
+	if err := obs.Cancel(ctx); err != nil {
+		t.Errorf("Error cancelling observation: %s", err)
+	}
+	res.mutex.Lock()
+	defer res.mutex.Unlock()
+	if n := len(res.observers); n != 0 {
+		t.Errorf("Expected no observers, got %v", n)
+	}
+}
+
+func TestIngestHandler(t *testing.T) {
+	senml.AutoTime = false
+	received := make(chan []senml.Measurement, 1)
+	router := mux.NewRouter()
+	var buf bytes.Buffer
+	h := NewIngestHandler(func(req *mux.Message, list []senml.Measurement) error {
+		if list[0].Attrs().Name == "invalid" {
+			return errors.New("database password rejected")
+		}
+		received <- list
+		return nil
+	})
+	h.ErrorLog = log.New(&buf, "", 0)
+	router.Handle("/ingest", h)
+	addr, stop := testServer(t, router)
+	defer stop()
+
+	c := testClient(t, addr)
+	defer c.Close()
+
+	for _, cf := range []senml.ContentFormat{senml.ContentFormatJSON, senml.ContentFormatCBOR, senml.ContentFormatXML} {
+		c.ContentFormat = cf
+		ctx, cancel := testContext()
+		err := c.Post(ctx, "/ingest", testMeasurements)
+		cancel()
+		if err != nil {
+			t.Errorf("Error posting as %v: %s", cf, err)
+			continue
+		}
+		if list := <-received; !senmltest.Equal(list, testMeasurements) {
+			t.Errorf("Received measurements as %v incorrect, got:\n%v\nexpected:\n%v", cf, list, testMeasurements)
+		}
+	}
+
+	ctx, cancel := testContext()
+	defer cancel()
+	var rerr *ResponseError
+	err := c.Post(ctx, "/ingest", []senml.Measurement{senml.NewValue("invalid", 1, senml.None, time.Time{}, 0)})
+	if !errors.As(err, &rerr) || rerr.Code != codes.InternalServerError || rerr.Message != "Internal Server Error" {
+		t.Errorf("Expected Internal Server Error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "database password rejected") {
+		t.Errorf("Internal error not logged, got: %q", buf.String())
+	}
+	if _, err := c.Get(ctx, "/ingest"); !errors.As(err, &rerr) || rerr.Code != codes.MethodNotAllowed {
+		t.Errorf("Expected Method Not Allowed, got: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package coap
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/plgd-dev/go-coap/v2/message/codes"
+	"github.com/plgd-dev/go-coap/v2/mux"
+	"github.com/silkeh/senml"
+)
+
+// Receiver processes the measurements received in a request.
+type Receiver func(req *mux.Message, list []senml.Measurement) error
+
+// IngestHandler decodes packs sent in POST or PUT requests, and passes the measurements to a Receiver.
+// The format of the payload is determined by the Content-Format option.
+// A Changed response is returned if the Receiver returns no error.
+type IngestHandler struct {
+	Receiver Receiver
+
+	// ErrorLog is used for logging errors of the receiver.
+	// The standard logger is used if this is nil.
+	ErrorLog *log.Logger
+}
+
+// NewIngestHandler returns a new IngestHandler passing measurements to the given receiver.
+func NewIngestHandler(receiver Receiver) *IngestHandler {
+	return &IngestHandler{Receiver: receiver}
+}
+
+// ServeCOAP decodes the measurements in the request and passes them to the receiver.
+func (h *IngestHandler) ServeCOAP(w mux.ResponseWriter, req *mux.Message) {
+	if req.Code != codes.POST && req.Code != codes.PUT {
+		setError(w, codes.MethodNotAllowed, fmt.Errorf("method %v not allowed", req.Code))
+		return
+	}
+
+	cf, err := req.Options.ContentFormat()
+	if err != nil {
+		setError(w, codes.UnsupportedMediaType, fmt.Errorf("missing Content-Format"))
+		return
+	}
+	if _, ok := lookupCodec(senml.ContentFormat(cf)); !ok {
+		setError(w, codes.UnsupportedMediaType, fmt.Errorf("unsupported Content-Format %v", cf))
+		return
+	}
+
+	list, err := decode(req.Message)
+	if err != nil {
+		setError(w, codes.BadRequest, err)
+		return
+	}
+
+	if err := h.Receiver(req, list); err != nil {
+		setInternalError(w, err, h.ErrorLog)
+		return
+	}
+
+	w.SetResponse(codes.Changed, 0, nil)
+}
@@ -0,0 +1,264 @@
+package coap
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/silkeh/senml"
+)
+
+// Interface descriptions used in links, as defined by the CoRE Interfaces.
+const (
+	InterfaceBatch             = "core.b"
+	InterfaceSensor            = "core.s"
+	InterfaceReadOnlyParameter = "core.rp"
+)
+
+// Link is a link in a CoRE Link Format document, as defined in RFC 6690.
+type Link struct {
+	// Target is the URI reference of the linked resource, eg: "/sensor/temperature".
+	Target string
+
+	// ResourceType is the resource type (rt) of the resource, eg: "temperature".
+	ResourceType string
+
+	// Interface is the interface description (if) of the resource, eg: "core.s".
+	Interface string
+
+	// ContentFormats are the CoAP Content-Formats (ct) of the resource.
+	ContentFormats []senml.ContentFormat
+
+	// Unit is the SenML unit (units) of the values of the resource.
+	Unit senml.Unit
+}
+
+// String returns the link in the CoRE Link Format.
+func (l Link) String() string {
+	var b strings.Builder
+	b.WriteString("<" + l.Target + ">")
+	if l.ResourceType != "" {
+		b.WriteString(`;rt="` + l.ResourceType + `"`)
+	}
+	if l.Interface != "" {
+		b.WriteString(`;if="` + l.Interface + `"`)
+	}
+	if len(l.ContentFormats) > 0 {
+		cts := make([]string, len(l.ContentFormats))
+		for i, cf := range l.ContentFormats {
+			cts[i] = strconv.Itoa(int(cf))
+		}
+		b.WriteString(`;ct="` + strings.Join(cts, " ") + `"`)
+	}
+	if l.Unit != senml.None {
+		b.WriteString(`;units="` + string(l.Unit) + `"`)
+	}
+	return b.String()
+}
+
+// Links returns the links to the measurements in a pack.
+// The first link refers to the base name of the pack and uses the batch interface,
+// followed by one link per resolved name, relative to the given path prefix.
+// The resource type is derived from the kind of quantity of the unit,
+// or from the type of measurement if the kind is unknown.
+// All Content-Formats of the registered codecs are listed.
+func Links(prefix string, list []senml.Measurement) []Link {
+	cfs := contentFormats()
+	links := make([]Link, 0, len(list)+1)
+
+	if records := senml.Encode(list); len(records) > 0 && records[0].BaseName != "" {
+		links = append(links, Link{
+			Target:         prefix + records[0].BaseName,
+			Interface:      InterfaceBatch,
+			ContentFormats: cfs,
+		})
+	}
+
+	seen := make(map[string]bool, len(list))
+	for _, m := range list {
+		a := m.Attrs()
+		if seen[a.Name] {
+			continue
+		}
+		seen[a.Name] = true
+
+		l := Link{
+			Target:         prefix + a.Name,
+			ResourceType:   resourceType(m),
+			Interface:      InterfaceSensor,
+			ContentFormats: cfs,
+			Unit:           a.Unit,
+		}
+		switch m.(type) {
+		case *senml.String, *senml.Boolean, *senml.Data, *senml.ObjectLink:
+			l.Interface = InterfaceReadOnlyParameter
+		}
+		links = append(links, l)
+	}
+
+	return links
+}
+
+// contentFormats returns the sorted Content-Formats of all registered codecs
+// of SenML and SensML packs.
+func contentFormats() []senml.ContentFormat {
+	var cfs []senml.ContentFormat
+	for _, n := range senml.Codecs() {
+		c, ok := senml.LookupCodec(n)
+		if !ok || c.ContentFormat() == 0 {
+			continue
+		}
+		if mt := c.MediaType(); strings.HasPrefix(mt, "application/senml+") || strings.HasPrefix(mt, "application/sensml+") {
+			cfs = append(cfs, c.ContentFormat())
+		}
+	}
+	sort.Slice(cfs, func(i, j int) bool { return cfs[i] < cfs[j] })
+	return cfs
+}
+
+// resourceType returns the resource type of a measurement.
+// Spaces are replaced, as a resource type attribute contains a space separated list.
+func resourceType(m senml.Measurement) string {
+	if info := m.Attrs().Unit.Info(); info.Kind != "" {
+		return strings.Replace(info.Kind, " ", "-", -1)
+	}
+
+	switch m.(type) {
+	case *senml.Value:
+		return "value"
+	case *senml.Sum:
+		return "sum"
+	case *senml.String:
+		return "string"
+	case *senml.Boolean:
+		return "boolean"
+	case *senml.Data:
+		return "data"
+	case *senml.ObjectLink:
+		return "objectlink"
+	default:
+		return ""
+	}
+}
+
+// EncodeLinkFormat encodes a list of links into a CoRE Link Format document.
+func EncodeLinkFormat(links []Link) []byte {
+	var buf bytes.Buffer
+	for i, l := range links {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(l.String())
+	}
+	return buf.Bytes()
+}
+
+// DecodeLinkFormat decodes the links in a CoRE Link Format document.
+// Attributes other than rt, if, ct and units are ignored.
+func DecodeLinkFormat(b []byte) ([]Link, error) {
+	var links []Link
+	s := strings.TrimSpace(string(b))
+	for s != "" {
+		if s[0] != '<' {
+			return nil, fmt.Errorf("expected '<' at start of link, got %q", s)
+		}
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated link target in %q", s)
+		}
+
+		l := Link{Target: s[1:end]}
+		s = strings.TrimSpace(s[end+1:])
+
+		for s != "" && s[0] == ';' {
+			var name, value string
+			var err error
+			name, value, s, err = parseLinkParam(s[1:])
+			if err != nil {
+				return nil, err
+			}
+			if err := l.setParam(name, value); err != nil {
+				return nil, fmt.Errorf("invalid link <%s>: %w", l.Target, err)
+			}
+		}
+
+		links = append(links, l)
+		if s == "" {
+			break
+		}
+		if s[0] != ',' {
+			return nil, fmt.Errorf("expected ',' after link <%s>, got %q", l.Target, s)
+		}
+		s = strings.TrimSpace(s[1:])
+	}
+
+	return links, nil
+}
+
+// parseLinkParam parses a link parameter, returning its name, value and the remaining string.
+func parseLinkParam(s string) (name, value, rest string, err error) {
+	i := strings.IndexAny(s, "=;,")
+	if i < 0 {
+		return strings.TrimSpace(s), "", "", nil
+	}
+	name = strings.TrimSpace(s[:i])
+	if name == "" {
+		return "", "", "", fmt.Errorf("empty link parameter name in %q", s)
+	}
+	if s[i] != '=' {
+		return name, "", s[i:], nil
+	}
+
+	s = strings.TrimSpace(s[i+1:])
+	if s != "" && s[0] == '"' {
+		end := strings.IndexByte(s[1:], '"')
+		if end < 0 {
+			return "", "", "", fmt.Errorf("unterminated quoted value of link parameter %q", name)
+		}
+		return name, s[1 : end+1], strings.TrimSpace(s[end+2:]), nil
+	}
+
+	end := strings.IndexAny(s, ";,")
+	if end < 0 {
+		end = len(s)
+	}
+	return name, strings.TrimSpace(s[:end]), s[end:], nil
+}
+
+// setParam sets the attribute of the link for a link parameter.
+func (l *Link) setParam(name, value string) error {
+	switch name {
+	case "rt":
+		l.ResourceType = value
+	case "if":
+		l.Interface = value
+	case "units":
+		l.Unit = senml.Unit(value)
+	case "ct":
+		for _, f := range strings.Fields(value) {
+			cf, err := strconv.ParseUint(f, 10, 16)
+			if err != nil {
+				return fmt.Errorf("invalid Content-Format %q", f)
+			}
+			l.ContentFormats = append(l.ContentFormats, senml.ContentFormat(cf))
+		}
+	}
+	return nil
+}
+
+// ContentFormatIndex returns the Content-Formats of the links by name.
+// Names are derived from the link targets by removing the given path prefix,
+// and links with targets outside of the prefix are ignored.
+func ContentFormatIndex(prefix string, links []Link) map[string][]senml.ContentFormat {
+	index := make(map[string][]senml.ContentFormat, len(links))
+	for _, l := range links {
+		if !strings.HasPrefix(l.Target, prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(l.Target, prefix)
+		index[name] = append(index[name], l.ContentFormats...)
+	}
+	return index
+}
@@ -0,0 +1,98 @@
+package coap
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/silkeh/senml"
+)
+
+func TestLinks(t *testing.T) {
+	list := append(testMeasurements,
+		senml.NewString("sensor:state", "ok", senml.None, time.Unix(1555487588, 0), 0),
+		senml.NewValue("sensor:temperature", 23.7, senml.Celsius, time.Unix(1555487598, 0), 0),
+	)
+	cfs := contentFormats()
+	expCFs := []senml.ContentFormat{
+		senml.ContentFormatJSON, senml.ContentFormatSensMLJSON, senml.ContentFormatCBOR,
+		senml.ContentFormatSensMLCBOR, senml.ContentFormatXML, senml.ContentFormatSensMLXML,
+	}
+	if !reflect.DeepEqual(cfs, expCFs) {
+		t.Errorf("Content-Formats incorrect, got %v, expected %v", cfs, expCFs)
+	}
+
+	expected := []Link{
+		{Target: "/sensor:", Interface: InterfaceBatch, ContentFormats: cfs},
+		{Target: "/sensor:temperature", ResourceType: "temperature", Interface: InterfaceSensor, ContentFormats: cfs, Unit: senml.Celsius},
+		{Target: "/sensor:humidity", ResourceType: "relative-humidity", Interface: InterfaceSensor, ContentFormats: cfs, Unit: senml.RelativeHumidityPercent},
+		{Target: "/sensor:state", ResourceType: "string", Interface: InterfaceReadOnlyParameter, ContentFormats: cfs},
+	}
+
+	links := Links("/", list)
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Links incorrect, got:\n%v\nexpected:\n%v", links, expected)
+	}
+}
+
+func TestEncodeLinkFormat(t *testing.T) {
+	links := []Link{
+		{Target: "/sensor:", Interface: InterfaceBatch, ContentFormats: []senml.ContentFormat{110, 112}},
+		{Target: "/sensor:temperature", ResourceType: "temperature", Interface: InterfaceSensor, ContentFormats: []senml.ContentFormat{110}, Unit: senml.Celsius},
+	}
+	expected := `</sensor:>;if="core.b";ct="110 112",</sensor:temperature>;rt="temperature";if="core.s";ct="110";units="Cel"`
+
+	if b := EncodeLinkFormat(links); string(b) != expected {
+		t.Errorf("Encoding incorrect, got:\n%s\nexpected:\n%s", b, expected)
+	}
+}
+
+func TestDecodeLinkFormat(t *testing.T) {
+	tests := map[string][]Link{
+		`</sensor:>;if="core.b";ct="110 112",</sensor:temperature>;rt="temperature";if="core.s";ct="110";units="Cel"`: {
+			{Target: "/sensor:", Interface: InterfaceBatch, ContentFormats: []senml.ContentFormat{110, 112}},
+			{Target: "/sensor:temperature", ResourceType: "temperature", Interface: InterfaceSensor, ContentFormats: []senml.ContentFormat{110}, Unit: senml.Celsius},
+		},
+		"</a>;ct=112;obs, </b>;title=\"x, y;z\";rt=\"b\"\n,</c>": {
+			{Target: "/a", ContentFormats: []senml.ContentFormat{112}},
+			{Target: "/b", ResourceType: "b"},
+			{Target: "/c"},
+		},
+		"": nil,
+	}
+
+	for doc, expected := range tests {
+		links, err := DecodeLinkFormat([]byte(doc))
+		if err != nil {
+			t.Errorf("Error decoding %q: %s", doc, err)
+			continue
+		}
+		if !reflect.DeepEqual(links, expected) {
+			t.Errorf("Decoding of %q incorrect, got:\n%v\nexpected:\n%v", doc, links, expected)
+		}
+	}
+
+	for _, doc := range []string{"/a", "</a", `</a>;rt="x`, "</a>;ct=x", "</a> </b>", "</a>;=x"} {
+		if _, err := DecodeLinkFormat([]byte(doc)); err == nil {
+			t.Errorf("Expected error decoding %q", doc)
+		}
+	}
+}
+
+func TestContentFormatIndex(t *testing.T) {
+	links, err := DecodeLinkFormat(EncodeLinkFormat(Links("/s/", testMeasurements)))
+	if err != nil {
+		t.Fatalf("Error decoding: %s", err)
+	}
+	links = append(links, Link{Target: "/other", ContentFormats: []senml.ContentFormat{110}})
+
+	index := ContentFormatIndex("/s/", links)
+	expected := map[string][]senml.ContentFormat{
+		"sensor:":            contentFormats(),
+		"sensor:temperature": contentFormats(),
+		"sensor:humidity":    contentFormats(),
+	}
+	if !reflect.DeepEqual(index, expected) {
+		t.Errorf("Index incorrect, got:\n%v\nexpected:\n%v", index, expected)
+	}
+}
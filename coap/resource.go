@@ -0,0 +1,139 @@
+package coap
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/plgd-dev/go-coap/v2/message"
+	"github.com/plgd-dev/go-coap/v2/message/codes"
+	"github.com/plgd-dev/go-coap/v2/mux"
+	"github.com/silkeh/senml"
+)
+
+// observer represents a client observing a resource.
+type observer struct {
+	Client        mux.Client
+	Token         message.Token
+	ContentFormat senml.ContentFormat
+}
+
+// Resource is an observable CoAP resource serving a list of measurements.
+// The Content-Format of the response is selected using the Accept option,
+// and a Not Acceptable response is returned if it is not supported.
+// Clients registered using the Observe option are notified when the
+// measurements are changed using Set.
+type Resource struct {
+	// ErrorLog is used for logging errors of the encoder.
+	// The standard logger is used if this is nil.
+	ErrorLog *log.Logger
+
+	mutex     sync.Mutex
+	list      []senml.Measurement
+	observers map[string]*observer
+	sequence  uint32
+}
+
+// NewResource returns a new Resource with the given measurements.
+func NewResource(list []senml.Measurement) *Resource {
+	return &Resource{list: list, observers: make(map[string]*observer), sequence: 2}
+}
+
+// Measurements returns the current measurements of the resource.
+func (r *Resource) Measurements() []senml.Measurement {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.list
+}
+
+// Set changes the measurements of the resource and notifies all observers.
+// Observers that cannot be notified are removed.
+func (r *Resource) Set(list []senml.Measurement) {
+	r.mutex.Lock()
+	r.list = list
+	r.sequence = (r.sequence + 1) & 0xffffff
+	sequence := r.sequence
+	observers := make(map[string]*observer, len(r.observers))
+	for key, o := range r.observers {
+		observers[key] = o
+	}
+	r.mutex.Unlock()
+
+	failed := make(map[string]*observer)
+	for key, o := range observers {
+		if err := notify(o, list, sequence); err != nil {
+			failed[key] = o
+		}
+	}
+	if len(failed) == 0 {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for key, o := range failed {
+		if r.observers[key] == o {
+			delete(r.observers, key)
+		}
+	}
+}
+
+// notify sends a notification with the given measurements and sequence number to an observer.
+func notify(o *observer, list []senml.Measurement, sequence uint32) error {
+	b, err := encode(o.ContentFormat, list)
+	if err != nil {
+		return err
+	}
+
+	var opts message.Options
+	opts = opts.Add(uintOption(message.Observe, sequence))
+	opts = opts.Add(uintOption(message.ContentFormat, uint32(o.ContentFormat)))
+
+	return o.Client.WriteMessage(&message.Message{
+		Context: o.Client.Context(),
+		Token:   o.Token,
+		Code:    codes.Content,
+		Options: opts,
+		Body:    bytes.NewReader(b),
+	})
+}
+
+// ServeCOAP serves the measurements of the resource, and registers observers.
+func (r *Resource) ServeCOAP(w mux.ResponseWriter, req *mux.Message) {
+	if req.Code != codes.GET {
+		setError(w, codes.MethodNotAllowed, fmt.Errorf("method %v not allowed", req.Code))
+		return
+	}
+
+	cf := DefaultContentFormat
+	if a, err := req.Options.Accept(); err == nil {
+		cf = senml.ContentFormat(a)
+	}
+	if _, ok := lookupCodec(cf); !ok {
+		setError(w, codes.NotAcceptable, fmt.Errorf("unsupported Content-Format %v", cf))
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	b, err := encode(cf, r.list)
+	if err != nil {
+		setInternalError(w, err, r.ErrorLog)
+		return
+	}
+
+	var opts []message.Option
+	key := w.Client().RemoteAddr().String() + "/" + req.Token.String()
+	switch obs, err := req.Options.Observe(); {
+	case err != nil:
+	case obs == 0:
+		r.observers[key] = &observer{Client: w.Client(), Token: req.Token, ContentFormat: cf}
+		opts = append(opts, uintOption(message.Observe, r.sequence))
+	case obs == 1:
+		delete(r.observers, key)
+	}
+
+	w.SetResponse(codes.Content, message.MediaType(cf), bytes.NewReader(b), opts...)
+}
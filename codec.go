@@ -0,0 +1,153 @@
+package senml
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Codec represents an encoding of SenML records.
+type Codec interface {
+	// Encode encodes a list of records.
+	Encode(records []Record) ([]byte, error)
+
+	// Decode decodes a list of records.
+	Decode(b []byte) ([]Record, error)
+
+	// MediaType returns the media type of the encoding, eg: "application/senml+json".
+	MediaType() string
+
+	// ContentFormat returns the CoAP Content-Format of the encoding.
+	ContentFormat() ContentFormat
+}
+
+// recordCodec implements Codec using functions for encoding and decoding records.
+type recordCodec struct {
+	mediaType     string
+	contentFormat ContentFormat
+	encode        func([]Record) ([]byte, error)
+	decode        func([]byte) ([]Record, error)
+}
+
+// Encode encodes a list of records.
+func (c *recordCodec) Encode(records []Record) ([]byte, error) {
+	return c.encode(records)
+}
+
+// Decode decodes a list of records.
+func (c *recordCodec) Decode(b []byte) ([]Record, error) {
+	return c.decode(b)
+}
+
+// MediaType returns the media type of the encoding.
+func (c *recordCodec) MediaType() string {
+	return c.mediaType
+}
+
+// ContentFormat returns the CoAP Content-Format of the encoding.
+func (c *recordCodec) ContentFormat() ContentFormat {
+	return c.contentFormat
+}
+
+// codecRegistry contains all registered codecs by name.
+var (
+	codecRegistry      = make(map[string]Codec)
+	codecRegistryMutex sync.RWMutex
+)
+
+func init() {
+	for name, c := range map[string]*recordCodec{
+		"json":        {MediaTypeJSON, ContentFormatJSON, EncodeJSONRecords, DecodeJSONRecords},
+		"cbor":        {MediaTypeCBOR, ContentFormatCBOR, EncodeCBORRecords, DecodeCBORRecords},
+		"xml":         {MediaTypeXML, ContentFormatXML, EncodeXMLRecords, DecodeXMLRecords},
+		"sensml+json": {MediaTypeSensMLJSON, ContentFormatSensMLJSON, EncodeJSONRecords, DecodeJSONRecords},
+		"sensml+cbor": {MediaTypeSensMLCBOR, ContentFormatSensMLCBOR, EncodeCBORRecords, DecodeCBORRecords},
+		"sensml+xml":  {MediaTypeSensMLXML, ContentFormatSensMLXML, EncodeXMLRecords, DecodeXMLRecords},
+	} {
+		if err := RegisterCodec(name, c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterCodec registers a codec with the given name, eg: "json".
+// An error is returned if the name or the media type of the codec is already registered.
+func RegisterCodec(name string, c Codec) error {
+	codecRegistryMutex.Lock()
+	defer codecRegistryMutex.Unlock()
+
+	if name == "" {
+		return fmt.Errorf("cannot register codec without name")
+	}
+	if _, ok := codecRegistry[name]; ok {
+		return fmt.Errorf("codec %q is already registered", name)
+	}
+	for n, r := range codecRegistry {
+		if r.MediaType() == c.MediaType() {
+			return fmt.Errorf("media type %q is already registered by codec %q", c.MediaType(), n)
+		}
+	}
+
+	codecRegistry[name] = c
+	return nil
+}
+
+// LookupCodec returns the codec registered with the given name.
+func LookupCodec(name string) (Codec, bool) {
+	codecRegistryMutex.RLock()
+	defer codecRegistryMutex.RUnlock()
+
+	c, ok := codecRegistry[name]
+	return c, ok
+}
+
+// LookupMediaType returns the codec for the given media type.
+// Parameters of the media type are ignored.
+func LookupMediaType(mediaType string) (Codec, bool) {
+	codecRegistryMutex.RLock()
+	defer codecRegistryMutex.RUnlock()
+
+	mediaType = baseMediaType(mediaType)
+	for _, c := range codecRegistry {
+		if c.MediaType() == mediaType {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// encodeCodec encodes a list of measurements using the registered codec with the given name.
+func encodeCodec(name string, list []Measurement) ([]byte, error) {
+	c, ok := LookupCodec(name)
+	if !ok {
+		return nil, fmt.Errorf("codec %q is not registered", name)
+	}
+	return c.Encode(Encode(list))
+}
+
+// decodeCodec decodes a list of measurements using the registered codec with the given name.
+func decodeCodec(name string, b []byte) ([]Measurement, error) {
+	c, ok := LookupCodec(name)
+	if !ok {
+		return nil, fmt.Errorf("codec %q is not registered", name)
+	}
+
+	records, err := c.Decode(b)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(records)
+}
+
+// Codecs returns the names of all registered codecs, sorted by name.
+func Codecs() []string {
+	codecRegistryMutex.RLock()
+	names := make([]string, 0, len(codecRegistry))
+	for n := range codecRegistry {
+		names = append(names, n)
+	}
+	codecRegistryMutex.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,76 @@
+package senml
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+type testCodec struct{}
+
+func (testCodec) Encode(records []Record) ([]byte, error) { return EncodeJSONRecords(records) }
+func (testCodec) Decode(b []byte) ([]Record, error)       { return DecodeJSONRecords(b) }
+func (testCodec) MediaType() string                       { return "application/test+json" }
+func (testCodec) ContentFormat() ContentFormat            { return 65000 }
+
+func TestCodecs(t *testing.T) {
+	for _, n := range []string{"json", "cbor", "xml", "sensml+json", "sensml+cbor", "sensml+xml"} {
+		c, ok := LookupCodec(n)
+		if !ok {
+			t.Errorf("Codec %q not registered", n)
+			continue
+		}
+		if f, ok := LookupContentFormat(c.MediaType()); !ok || f != c.ContentFormat() {
+			t.Errorf("Content-Format of codec %q incorrect, got %v, expected %v", n, c.ContentFormat(), f)
+		}
+		if m, ok := LookupMediaType(c.MediaType() + "; charset=utf-8"); !ok || m != c {
+			t.Errorf("Codec for media type %q incorrect", c.MediaType())
+		}
+	}
+
+	if c, ok := LookupMediaType(MediaTypeEXI); ok {
+		t.Errorf("Unexpected codec for %q: %v", MediaTypeEXI, c)
+	}
+}
+
+// unregisterCodec removes a codec registered in a test from the registry.
+func unregisterCodec(name string) {
+	codecRegistryMutex.Lock()
+	defer codecRegistryMutex.Unlock()
+	delete(codecRegistry, name)
+}
+
+func TestRegisterCodec(t *testing.T) {
+	if err := RegisterCodec("test", testCodec{}); err != nil {
+		t.Fatalf("Error registering codec: %s", err)
+	}
+	defer unregisterCodec("test")
+
+	b, err := EncodeMediaType("application/test+json", []Measurement{NewValue("test", 1, None, time.Unix(1555487588, 0), 0)})
+	exp := `[{"n":"test","v":1,"t":1555487588}]`
+	if err != nil || string(b) != exp {
+		t.Errorf("Encoding with registered codec incorrect, got %s (%v), expected %s", b, err, exp)
+	}
+
+	names := Codecs()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("Codecs are not sorted: %v", names)
+	}
+	if i := sort.SearchStrings(names, "test"); i == len(names) || names[i] != "test" {
+		t.Errorf("Registered codec not listed: %v", names)
+	}
+
+	errorTests := map[string]struct {
+		Name  string
+		Codec Codec
+	}{
+		"Empty name":           {"", testCodec{}},
+		"Duplicate name":       {"json", testCodec{}},
+		"Duplicate media type": {"test2", testCodec{}},
+	}
+	for n, test := range errorTests {
+		if err := RegisterCodec(test.Name, test.Codec); err == nil {
+			t.Errorf("Expected error for %s", n)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package senml
+
+import (
+	"fmt"
+	"math"
+)
+
+// conversion represents the relation between a unit and a reference unit.
+// A value in the reference unit is calculated as value*Scale + Offset.
+type conversion struct {
+	Unit   Unit
+	Scale  float64
+	Offset float64
+}
+
+// equivalentUnits contains conversions between primary SenML units that are
+// not defined in the registry, but describe the same quantity.
+var equivalentUnits = map[Unit]conversion{
+	Celsius:        {Kelvin, 1, 273.15},
+	Gram:           {Kilogram, 1.0 / 1000, 0},
+	Liter:          {CubicMeter, 1.0 / 1000, 0},
+	LiterPerSecond: {CubicMeterPerSecond, 1.0 / 1000, 0},
+	Ratio2:         {Ratio, 1.0 / 100, 0},
+	RPM:            {Rate, 1.0 / 60, 0},
+	HeartRate:      {Rate, 1.0 / 60, 0},
+	Degree:         {Radian, math.Pi / 180, 0},
+	Byte:           {Bit, 8, 0},
+}
+
+// reference returns the reference unit of a unit,
+// and the conversion from the unit to that reference unit.
+func (u Unit) reference() conversion {
+	i := u.Info()
+	c := conversion{Unit: i.Primary, Scale: i.Scale, Offset: i.Offset}
+	if r, ok := equivalentUnits[c.Unit]; ok {
+		c = conversion{
+			Unit:   r.Unit,
+			Scale:  c.Scale * r.Scale,
+			Offset: c.Offset*r.Scale + r.Offset,
+		}
+	}
+	return c
+}
+
+// Primary returns the primary SenML unit of the unit.
+// Unknown units are returned as-is.
+func (u Unit) Primary() Unit {
+	return u.Info().Primary
+}
+
+// Convert converts a value in this unit to the given unit.
+// An error is returned if the units are incompatible.
+func (u Unit) Convert(value float64, to Unit) (float64, error) {
+	if u == to {
+		return value, nil
+	}
+
+	from, dst := u.reference(), to.reference()
+	if from.Unit != dst.Unit {
+		return 0, fmt.Errorf("cannot convert unit %q to %q", u, to)
+	}
+
+	return (value*from.Scale + from.Offset - dst.Offset) / dst.Scale, nil
+}
+
+// convertDifference converts a difference between two values in this unit to the given unit.
+// Only the scale of the units is applied, eg: a difference of 1 K is 1 Cel.
+// An error is returned if the units are incompatible.
+func (u Unit) convertDifference(value float64, to Unit) (float64, error) {
+	if u == to {
+		return value, nil
+	}
+
+	from, dst := u.reference(), to.reference()
+	if from.Unit != dst.Unit {
+		return 0, fmt.Errorf("cannot convert unit %q to %q", u, to)
+	}
+
+	return value * from.Scale / dst.Scale, nil
+}
+
+// Normalize returns a copy of a Value or Sum measurement converted to the
+// primary SenML unit of its unit.
+// Other measurements, and measurements already in a primary unit, are returned unchanged.
+func Normalize(m Measurement) Measurement {
+	u := m.Attrs().Unit
+	p := u.Primary()
+	if p == u {
+		return m
+	}
+
+	switch v := m.(type) {
+	case *Value:
+		n := *v
+		n.Unit = p
+		n.Value, _ = u.Convert(v.Value, p)
+		return &n
+	case *Sum:
+		n := *v
+		n.Unit = p
+		n.Value, _ = u.Convert(v.Value, p)
+		return &n
+	default:
+		return m
+	}
+}
@@ -0,0 +1,79 @@
+package senml
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestUnitConvert(t *testing.T) {
+	tests := []struct {
+		From   Unit
+		To     Unit
+		Value  float64
+		Result float64
+	}{
+		{Kelvin, Kelvin, 1, 1},
+		{KilowattHour, Joule, 1, 3600000},
+		{KilowattHour, WattHour, 1.5, 1500},
+		{Hectopascal, Pascal, 1013.25, 101325},
+		{Milliampere, Ampere, 250, 0.25},
+		{KilometerPerHour, MeterPerSecond, 36, 10},
+		{MeterPerSecond, KilometerPerHour, 10, 36},
+		{Millisecond, Minute, 90000, 1.5},
+		{DecibelMilliwatt, DBW, 30, 0},
+		{Celsius, Kelvin, 20, 293.15},
+		{Kelvin, Celsius, 0, -273.15},
+		{Percent, Ratio2, 50, 50},
+		{PartsPerMillion, Percent, 10000, 1},
+		{Degree, Radian, 180, math.Pi},
+		{Liter, CubicMeter, 1000, 1},
+		{MegabytePerSecond, MegabitPerSecond, 1, 8},
+		{Kibibyte, Bit, 1, 8192},
+	}
+
+	for _, test := range tests {
+		res, err := test.From.Convert(test.Value, test.To)
+		if err != nil {
+			t.Errorf("Error converting %v %s to %s: %s", test.Value, test.From, test.To, err)
+			continue
+		}
+		if math.Abs(res-test.Result) > 1e-9*math.Max(1, math.Abs(test.Result)) {
+			t.Errorf("Conversion of %v %s to %s incorrect, got %v, expected %v", test.Value, test.From, test.To, res, test.Result)
+		}
+	}
+}
+
+func TestUnitConvertIncompatible(t *testing.T) {
+	tests := [][2]Unit{
+		{Celsius, Meter},
+		{KilowattHour, Watt},
+		{Unit("foo"), Unit("bar")},
+		{None, Meter},
+	}
+
+	for _, test := range tests {
+		if _, err := test[0].Convert(1, test[1]); err == nil {
+			t.Errorf("Expected error converting %q to %q", test[0], test[1])
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		Input, Result Measurement
+	}{
+		{NewValue("p", 1000, Hectopascal, now, 0), NewValue("p", 100000, Pascal, now, 0)},
+		{NewSum("e", 2, KilowattHour, now, 0), NewSum("e", 7200000, Joule, now, 0)},
+		{NewValue("t", 20, Celsius, now, 0), NewValue("t", 20, Celsius, now, 0)},
+		{NewString("s", "x", Millisecond, now, 0), NewString("s", "x", Millisecond, now, 0)},
+	}
+
+	for _, test := range tests {
+		res := Normalize(test.Input)
+		if !res.Equal(test.Result) {
+			t.Errorf("Normalize incorrect, got:\n%#v\nexpected:\n%#v", res, test.Result)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package senml
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -9,6 +10,44 @@ import (
 // Disabling this option results in timestamps relative to zero time when no exact time is given.
 var AutoTime = true
 
+// UnitPolicy represents the handling of units that are neither standard nor registered.
+type UnitPolicy int
+
+// Supported unit policies.
+const (
+	// AllowUnknownUnits accepts any unit.
+	AllowUnknownUnits UnitPolicy = iota
+
+	// FlagUnknownUnits decodes all records, but returns an UnknownUnitError
+	// together with the result if any unknown units are encountered.
+	FlagUnknownUnits
+
+	// RejectUnknownUnits returns an UnknownUnitError instead of a result
+	// if any unknown unit is encountered.
+	RejectUnknownUnits
+)
+
+// UnknownUnits sets the handling of unknown units in Decode.
+var UnknownUnits = AllowUnknownUnits
+
+// UnknownUnit represents the occurrence of an unknown unit in a record.
+type UnknownUnit struct {
+	Record int
+	Unit   Unit
+}
+
+// UnknownUnitError is returned when unknown units are encountered during decoding.
+type UnknownUnitError []UnknownUnit
+
+// Error returns the error message.
+func (e UnknownUnitError) Error() string {
+	strs := make([]string, len(e))
+	for i, u := range e {
+		strs[i] = fmt.Sprintf("unknown unit %q in record %d", u.Unit, u.Record)
+	}
+	return strings.Join(strs, ", ")
+}
+
 // Decode decodes a list of Measurement records into measurement values.
 func Decode(records []Record) (list []Measurement, err error) {
 	list = make([]Measurement, len(records))
@@ -23,16 +62,20 @@ func Decode(records []Record) (list []Measurement, err error) {
 	var baseUnit Unit
 	var baseValue Numeric
 	var baseSum Numeric
+	var unknownUnits UnknownUnitError
 
 	for i, o := range records {
+		recordBaseUnit := decodeUnit(o.BaseUnit)
+		recordUnit := decodeUnit(o.Unit)
+
 		if o.BaseName != "" {
 			baseName = o.BaseName
 		}
 		if o.BaseTime != nil {
 			baseTime = o.BaseTime
 		}
-		if o.BaseUnit != "" {
-			baseUnit = Unit(o.BaseUnit)
+		if recordBaseUnit != None {
+			baseUnit = recordBaseUnit
 		}
 		if o.BaseValue != nil {
 			baseValue = o.BaseValue
@@ -41,9 +84,20 @@ func Decode(records []Record) (list []Measurement, err error) {
 			baseSum = o.BaseSum
 		}
 
+		if UnknownUnits != AllowUnknownUnits {
+			for _, u := range []Unit{recordBaseUnit, recordUnit} {
+				if !u.Known() {
+					unknownUnits = append(unknownUnits, UnknownUnit{Record: i, Unit: u})
+				}
+			}
+			if len(unknownUnits) > 0 && UnknownUnits == RejectUnknownUnits {
+				return nil, unknownUnits
+			}
+		}
+
 		var unit Unit
-		if o.Unit != "" {
-			unit = Unit(o.Unit)
+		if recordUnit != None {
+			unit = recordUnit
 		} else {
 			unit = baseUnit
 		}
@@ -66,10 +120,16 @@ func Decode(records []Record) (list []Measurement, err error) {
 			list[i] = &Data{Attributes: m, Value: o.DataValue}
 		case o.BooleanValue != nil:
 			list[i] = &Boolean{Attributes: m, Value: *o.BooleanValue}
+		case o.ObjectLink != "":
+			list[i] = &ObjectLink{Attributes: m, Value: o.ObjectLink}
 		default:
 			return nil, fmt.Errorf("record has no value attribute set: %#v", o)
 		}
 	}
 
+	if len(unknownUnits) > 0 {
+		return list, unknownUnits
+	}
+
 	return list, nil
 }
@@ -0,0 +1,269 @@
+package senml
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale represents a language used for displaying units and measurements.
+// Locales are identified by their ISO 639-1 language code,
+// any region suffix (eg: "de-DE") is ignored.
+type Locale string
+
+// Supported locales.
+const (
+	English Locale = "en"
+	German  Locale = "de"
+)
+
+// language returns the language of the locale, falling back to English.
+func (l Locale) language() Locale {
+	s := strings.ToLower(string(l))
+	if i := strings.IndexAny(s, "-_"); i >= 0 {
+		s = s[:i]
+	}
+	switch Locale(s) {
+	case German:
+		return German
+	default:
+		return English
+	}
+}
+
+// unitSymbols contains the display symbols of units that differ from the SenML symbol.
+var unitSymbols = map[Locale]map[Unit]string{
+	English: {
+		Ohm:                      "Ω",
+		Celsius:                  "°C",
+		SquareMeter:              "m²",
+		CubicMeter:               "m³",
+		MeterPerSquareSecond:     "m/s²",
+		CubicMeterPerSecond:      "m³/s",
+		WattPerSquareMeter:       "W/m²",
+		CandelaPerSquareMeter:    "cd/m²",
+		Latitude:                 "°",
+		Longitude:                "°",
+		Bel:                      "B SPL",
+		Count:                    "",
+		Ratio:                    "",
+		RelativeHumidityPercent:  "% RH",
+		RemainingBatteryPercent:  "%",
+		RemainingBatterySeconds:  "s",
+		HeartRate:                "bpm",
+		HeartBeats:               "beats",
+		VoltAmpereSecond:         "VA·s",
+		VoltAmpereReactiveSecond: "var·s",
+		KilogramPerCubicMeter:    "kg/m³",
+		Degree:                   "°",
+		MicrogramPerCubicMeter:   "µg/m³",
+		Percent:                  "%",
+		Permille:                 "‰",
+		Microgram:                "µg/l",
+	},
+	German: {
+		RelativeHumidityPercent: "% rF",
+		HeartRate:               "Schläge/min",
+		HeartBeats:              "Schläge",
+	},
+}
+
+// unitNames contains the display names of units for locales other than English.
+// English names are taken from the unit description.
+var unitNames = map[Locale]map[Unit]string{
+	German: {
+		Meter:                      "Meter",
+		Kilogram:                   "Kilogramm",
+		Gram:                       "Gramm",
+		Second:                     "Sekunde",
+		Ampere:                     "Ampere",
+		Kelvin:                     "Kelvin",
+		Candela:                    "Candela",
+		Mole:                       "Mol",
+		Hertz:                      "Hertz",
+		Radian:                     "Radiant",
+		Steradian:                  "Steradiant",
+		Newton:                     "Newton",
+		Pascal:                     "Pascal",
+		Joule:                      "Joule",
+		Watt:                       "Watt",
+		Coulomb:                    "Coulomb",
+		Volt:                       "Volt",
+		Farad:                      "Farad",
+		Ohm:                        "Ohm",
+		Siemens:                    "Siemens",
+		Weber:                      "Weber",
+		Tesla:                      "Tesla",
+		Henry:                      "Henry",
+		Celsius:                    "Grad Celsius",
+		Lumen:                      "Lumen",
+		Lux:                        "Lux",
+		Becquerel:                  "Becquerel",
+		Gray:                       "Gray",
+		Sievert:                    "Sievert",
+		Katal:                      "Katal",
+		SquareMeter:                "Quadratmeter",
+		CubicMeter:                 "Kubikmeter",
+		Liter:                      "Liter",
+		MeterPerSecond:             "Meter pro Sekunde",
+		MeterPerSquareSecond:       "Meter pro Quadratsekunde",
+		CubicMeterPerSecond:        "Kubikmeter pro Sekunde",
+		LiterPerSecond:             "Liter pro Sekunde",
+		WattPerSquareMeter:         "Watt pro Quadratmeter",
+		CandelaPerSquareMeter:      "Candela pro Quadratmeter",
+		Bit:                        "Bit",
+		BitPerSecond:               "Bit pro Sekunde",
+		Latitude:                   "Grad Breite",
+		Longitude:                  "Grad Länge",
+		PH:                         "pH-Wert",
+		Decibel:                    "Dezibel",
+		DBW:                        "Dezibel bezogen auf 1 W",
+		Bel:                        "Bel",
+		Count:                      "Zählerwert",
+		Ratio:                      "Verhältnis",
+		Ratio2:                     "Verhältnis",
+		RelativeHumidityPercent:    "Prozent relative Luftfeuchtigkeit",
+		RemainingBatteryPercent:    "Prozent verbleibende Batterieladung",
+		RemainingBatterySeconds:    "Sekunden verbleibende Batterielaufzeit",
+		Rate:                       "Ereignisse pro Sekunde",
+		RPM:                        "Ereignisse pro Minute",
+		HeartRate:                  "Schläge pro Minute",
+		HeartBeats:                 "Herzschläge",
+		Conductivity:               "Siemens pro Meter",
+		Byte:                       "Byte",
+		VoltAmpere:                 "Voltampere",
+		VoltAmpereSecond:           "Voltamperesekunde",
+		VoltAmpereReactive:         "Var",
+		VoltAmpereReactiveSecond:   "Varsekunde",
+		JoulePerMeter:              "Joule pro Meter",
+		KilogramPerCubicMeter:      "Kilogramm pro Kubikmeter",
+		Degree:                     "Grad",
+		NephelometricTurbidityUnit: "Nephelometrische Trübungseinheit",
+		Millisecond:                "Millisekunde",
+		Minute:                     "Minute",
+		Hour:                       "Stunde",
+		Megahertz:                  "Megahertz",
+		Kilowatt:                   "Kilowatt",
+		KilovoltAmpere:             "Kilovoltampere",
+		Kilovar:                    "Kilovar",
+		AmpereHour:                 "Amperestunde",
+		WattHour:                   "Wattstunde",
+		KilowattHour:               "Kilowattstunde",
+		VarHour:                    "Varstunde",
+		KilovarHour:                "Kilovarstunde",
+		KilovoltAmpereHour:         "Kilovoltamperestunde",
+		WattHourPerKilometer:       "Wattstunde pro Kilometer",
+		Kibibyte:                   "Kibibyte",
+		Gigabyte:                   "Gigabyte",
+		MegabitPerSecond:           "Megabit pro Sekunde",
+		BytePerSecond:              "Byte pro Sekunde",
+		MegabytePerSecond:          "Megabyte pro Sekunde",
+		Millivolt:                  "Millivolt",
+		Milliampere:                "Milliampere",
+		DecibelMilliwatt:           "Dezibel bezogen auf 1 mW",
+		MicrogramPerCubicMeter:     "Mikrogramm pro Kubikmeter",
+		MillimeterPerHour:          "Millimeter pro Stunde",
+		MeterPerHour:               "Meter pro Stunde",
+		PartsPerMillion:            "Teile pro Million",
+		Percent:                    "Prozent",
+		Permille:                   "Promille",
+		Hectopascal:                "Hektopascal",
+		Millimeter:                 "Millimeter",
+		Centimeter:                 "Zentimeter",
+		Kilometer:                  "Kilometer",
+		KilometerPerHour:           "Kilometer pro Stunde",
+		PartsPerBillion:            "Teile pro Milliarde",
+		PartsPerTrillion:           "Teile pro Billion",
+		VoltAmpereHour:             "Voltamperestunde",
+		Milligram:                  "Milligramm pro Liter",
+		Microgram:                  "Mikrogramm pro Liter",
+		GramPerLiter:               "Gramm pro Liter",
+	},
+}
+
+// Symbol returns the symbol of the unit for display in the given locale, eg: "°C".
+// The SenML symbol is returned for units without a specific display symbol.
+func (u Unit) Symbol(locale Locale) string {
+	l := locale.language()
+	if s, ok := unitSymbols[l][u]; ok {
+		return s
+	}
+	if s, ok := unitSymbols[English][u]; ok {
+		return s
+	}
+	return string(u)
+}
+
+// Name returns the name of the unit in the given locale, eg: "degrees Celsius".
+// The English name is returned if no translation is available,
+// and the SenML symbol is returned for unknown units.
+func (u Unit) Name(locale Locale) string {
+	if s, ok := unitNames[locale.language()][u]; ok {
+		return s
+	}
+	if i := u.Info(); i.Description != "" {
+		return i.Description
+	}
+	return string(u)
+}
+
+// displayWords contains the translations of words used in formatted measurements.
+var displayWords = map[Locale]map[string]string{
+	German: {
+		"at":    "am",
+		"true":  "wahr",
+		"false": "falsch",
+	},
+}
+
+// word returns the translation of a word in the locale.
+func (l Locale) word(w string) string {
+	if s, ok := displayWords[l.language()][w]; ok {
+		return s
+	}
+	return w
+}
+
+// formatFloat formats a floating point value in the locale.
+func (l Locale) formatFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if l.language() == German {
+		s = strings.Replace(s, ".", ",", 1)
+	}
+	return s
+}
+
+// Format returns a human readable representation of a measurement in the given locale,
+// eg: "sensor:temperature = 23.5 °C at 2024-01-02T15:04:05Z".
+func Format(m Measurement, locale Locale) string {
+	var value string
+	switch v := m.(type) {
+	case *Value:
+		value = locale.formatFloat(v.Value)
+	case *Sum:
+		value = locale.formatFloat(v.Value)
+	case *String:
+		value = strconv.Quote(v.Value)
+	case *Boolean:
+		value = locale.word(strconv.FormatBool(v.Value))
+	case *Data:
+		value = base64.StdEncoding.EncodeToString(v.Value)
+	case *ObjectLink:
+		value = v.Value
+	default:
+		value = fmt.Sprint(m)
+	}
+
+	a := m.Attrs()
+	s := a.Name + " = " + value
+	if sym := a.Unit.Symbol(locale); sym != "" {
+		s += " " + sym
+	}
+	if !a.Time.IsZero() {
+		s += " " + locale.word("at") + " " + a.Time.Format(time.RFC3339Nano)
+	}
+
+	return s
+}
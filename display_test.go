@@ -0,0 +1,64 @@
+package senml
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnitDisplay(t *testing.T) {
+	tests := []struct {
+		Unit         Unit
+		Locale       Locale
+		Symbol, Name string
+	}{
+		{Celsius, English, "°C", "degrees Celsius"},
+		{Celsius, German, "°C", "Grad Celsius"},
+		{Celsius, "de-AT", "°C", "Grad Celsius"},
+		{Celsius, "fr", "°C", "degrees Celsius"},
+		{KilowattHour, English, "kWh", "kilowatt-hour"},
+		{KilowattHour, German, "kWh", "Kilowattstunde"},
+		{RelativeHumidityPercent, English, "% RH", "percentage relative humidity"},
+		{RelativeHumidityPercent, German, "% rF", "Prozent relative Luftfeuchtigkeit"},
+		{Unit("foo"), English, "foo", "foo"},
+	}
+
+	for _, test := range tests {
+		if s := test.Unit.Symbol(test.Locale); s != test.Symbol {
+			t.Errorf("Symbol of %q in %q incorrect, got %q, expected %q", test.Unit, test.Locale, s, test.Symbol)
+		}
+		if s := test.Unit.Name(test.Locale); s != test.Name {
+			t.Errorf("Name of %q in %q incorrect, got %q, expected %q", test.Unit, test.Locale, s, test.Name)
+		}
+	}
+}
+
+func TestUnitNamesComplete(t *testing.T) {
+	for _, u := range Units() {
+		if _, ok := unitNames[German][u]; !ok {
+			t.Errorf("No German name for unit %q", u)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		Measurement Measurement
+		Locale      Locale
+		Result      string
+	}{
+		{NewValue("sensor:temperature", 23.5, Celsius, now, 0), English, "sensor:temperature = 23.5 °C at 2024-01-02T15:04:05Z"},
+		{NewValue("sensor:temperature", 23.5, Celsius, now, 0), German, "sensor:temperature = 23,5 °C am 2024-01-02T15:04:05Z"},
+		{NewSum("energy", 1200, KilowattHour, time.Time{}, 0), English, "energy = 1200 kWh"},
+		{NewValue("count", 5, Count, time.Time{}, 0), English, "count = 5"},
+		{NewBoolean("open", true, None, time.Time{}, 0), German, "open = wahr"},
+		{NewString("label", "Machine Room", None, time.Time{}, 0), English, `label = "Machine Room"`},
+		{NewData("nfc", []byte("hi \n"), None, time.Time{}, 0), English, "nfc = aGkgCg=="},
+	}
+
+	for _, test := range tests {
+		if s := Format(test.Measurement, test.Locale); s != test.Result {
+			t.Errorf("Format incorrect, got %q, expected %q", s, test.Result)
+		}
+	}
+}
@@ -15,6 +15,7 @@ func Encode(list []Measurement) (records []Record) {
 	baseTime := list[0].Attrs().Time
 	baseName := list[0].Attrs().Name
 	units := make(map[Unit]int)
+	var unitOrder []Unit
 	for _, v := range list {
 		m := v.Attrs()
 
@@ -31,6 +32,7 @@ func Encode(list []Measurement) (records []Record) {
 			units[m.Unit] += len(m.Unit)
 		} else {
 			units[m.Unit] = len(m.Unit)
+			unitOrder = append(unitOrder, m.Unit)
 		}
 	}
 
@@ -39,7 +41,7 @@ func Encode(list []Measurement) (records []Record) {
 	if _, ok := units[None]; ok {
 		baseUnit = None
 	} else {
-		baseUnit = maxUnit(units)
+		baseUnit = maxUnit(units, unitOrder)
 	}
 
 	// Clear bases when single record
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // TestEncode tests if encoding the expected result gives the same result as decoding the JSON into Objects.
@@ -36,3 +37,17 @@ func TestEncodeCompare(t *testing.T) {
 		t.Logf("Comparison for %s CBOR/JSON/XML (bytes):  %03d/%03d/%03d", n, len(c), len(j), len(x))
 	}
 }
+
+func TestEncodeBaseUnitTie(t *testing.T) {
+	now := time.Unix(1555487588, 0)
+	list := []Measurement{
+		NewValue("sensor:temperature", 23.5, Celsius, now, 0),
+		NewValue("sensor:humidity", 33.7, RelativeHumidityPercent, now, 0),
+	}
+
+	for i := 0; i < 10; i++ {
+		if records := Encode(list); records[0].BaseUnit != string(Celsius) {
+			t.Fatalf("Base unit incorrect, got %q, expected %q", records[0].BaseUnit, Celsius)
+		}
+	}
+}
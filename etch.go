@@ -0,0 +1,278 @@
+package senml
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/ugorji/go/codec"
+)
+
+// etchValueKeysJSON and etchValueKeysCBOR contain the keys of the value fields of a record.
+var (
+	etchValueKeysJSON = []string{"v", "vs", "vb", "vd", "s", "vlo"}
+	etchValueKeysCBOR = []interface{}{uint64(2), uint64(3), uint64(4), uint64(8), uint64(5), "vlo"}
+)
+
+// etchJSONCodec and etchCBORCodec implement the SenML etch formats.
+var (
+	etchJSONCodec = &recordCodec{MediaTypeEtchJSON, ContentFormatEtchJSON, EncodeEtchJSONRecords, DecodeEtchJSONRecords}
+	etchCBORCodec = &recordCodec{MediaTypeEtchCBOR, ContentFormatEtchCBOR, EncodeEtchCBORRecords, DecodeEtchCBORRecords}
+)
+
+// EtchJSONCodec returns the Codec for FETCH and iPATCH packs in SenML etch JSON.
+// It is not registered, as etch packs are not used for serving or ingesting measurements.
+func EtchJSONCodec() Codec {
+	return etchJSONCodec
+}
+
+// EtchCBORCodec returns the Codec for FETCH and iPATCH packs in SenML etch CBOR.
+// It is not registered, as etch packs are not used for serving or ingesting measurements.
+func EtchCBORCodec() Codec {
+	return etchCBORCodec
+}
+
+// EncodeEtchJSONRecords encodes a list of FETCH or iPATCH records into SenML etch JSON.
+// Records marked for removal are encoded with a null value.
+func EncodeEtchJSONRecords(records []Record) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, r := range records {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		b, err := json.Marshal(etchRecord(r))
+		if err != nil {
+			return nil, err
+		}
+		if r.Remove {
+			b = b[:len(b)-1]
+			if len(b) > 1 {
+				b = append(b, ',')
+			}
+			b = append(b, `"v":null}`...)
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte(']')
+
+	return buf.Bytes(), nil
+}
+
+// DecodeEtchJSONRecords decodes a list of FETCH or iPATCH records from SenML etch JSON.
+// Records with a null value are marked for removal.
+func DecodeEtchJSONRecords(j []byte) ([]Record, error) {
+	records, err := DecodeJSONRecords(j)
+	if err != nil {
+		return nil, err
+	}
+
+	var maps []map[string]json.RawMessage
+	if err := json.Unmarshal(j, &maps); err != nil {
+		return nil, err
+	}
+
+	for i, m := range maps {
+		for _, k := range etchValueKeysJSON {
+			if v, ok := m[k]; ok && string(v) == "null" {
+				records[i].Remove = true
+			}
+		}
+	}
+
+	return records, nil
+}
+
+// EncodeEtchCBORRecords encodes a list of FETCH or iPATCH records into SenML etch CBOR.
+// Records marked for removal are encoded with a null value.
+func EncodeEtchCBORRecords(records []Record) ([]byte, error) {
+	maps := make([]cborMap, len(records))
+	for i, r := range records {
+		r = etchRecord(r)
+		m, err := cborRecordMap(&r)
+		if err != nil {
+			return nil, err
+		}
+		if r.Remove {
+			m = append(m, uint64(2), nil)
+		}
+		maps[i] = m
+	}
+
+	var b []byte
+	err := codec.NewEncoderBytes(&b, &cbor).Encode(maps)
+	return b, err
+}
+
+// DecodeEtchCBORRecords decodes a list of FETCH or iPATCH records from SenML etch CBOR.
+// Records with a null value are marked for removal.
+func DecodeEtchCBORRecords(c []byte) ([]Record, error) {
+	records, err := DecodeCBORRecords(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var maps []map[interface{}]interface{}
+	if err := codec.NewDecoderBytes(c, &cbor).Decode(&maps); err != nil {
+		return nil, err
+	}
+
+	for i, m := range maps {
+		for _, k := range etchValueKeysCBOR {
+			if v, ok := m[k]; ok && v == nil {
+				records[i].Remove = true
+			}
+		}
+	}
+
+	return records, nil
+}
+
+// etchRecord returns the record without values if it is marked for removal.
+func etchRecord(r Record) Record {
+	if r.Remove {
+		r.Value, r.StringValue, r.BooleanValue, r.DataValue, r.Sum, r.ObjectLink = nil, "", nil, nil, nil, ""
+	}
+	return r
+}
+
+// etchRecords resolves the records of a FETCH or iPATCH pack into measurements.
+// Records without value, such as removals, result in a Value measurement.
+// The returned booleans indicate whether the record contains a time.
+func etchRecords(records []Record) ([]Measurement, []bool, error) {
+	resolved := make([]Record, len(records))
+	hasTime := make([]bool, len(records))
+
+	var baseTime Numeric
+	for i, r := range records {
+		if r.BaseTime != nil {
+			baseTime = r.BaseTime
+		}
+		hasTime[i] = baseTime != nil || r.Time != nil
+
+		r = etchRecord(r)
+		if r.Value == nil && r.StringValue == "" && r.BooleanValue == nil && len(r.DataValue) == 0 && r.Sum == nil && r.ObjectLink == "" {
+			r.Value = 0
+		}
+		resolved[i] = r
+	}
+
+	list, err := Decode(resolved)
+	if err != nil {
+		return nil, nil, err
+	}
+	return list, hasTime, nil
+}
+
+// ApplyFetch returns the measurements of a resource selected by the records
+// of a FETCH pack, as defined in RFC 8790.
+// Measurements are selected by their resolved name, and time if given in the
+// FETCH record, and returned in their original order.
+func ApplyFetch(resource []Measurement, fetch []Record) ([]Measurement, error) {
+	selectors, hasTime, err := etchRecords(fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Measurement
+	for _, m := range resource {
+		for i, s := range selectors {
+			if etchMatch(m, s, hasTime[i]) {
+				list = append(list, m)
+				break
+			}
+		}
+	}
+
+	return list, nil
+}
+
+// ApplyPatch applies the records of an iPATCH pack to the measurements of a
+// resource, as defined in RFC 8790.
+//
+// A patch record matches the measurements with the same resolved name,
+// and the same time if given in the patch record.
+// Matching measurements are replaced by the patch record,
+// or removed if the patch record has a null value (see Record.Remove).
+// The time of a measurement is retained if the patch record has no time.
+// Patch records without matching measurements are added to the resource.
+func ApplyPatch(resource []Measurement, patch []Record) ([]Measurement, error) {
+	patches, hasTime, err := etchRecords(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Measurement, len(resource))
+	copy(list, resource)
+
+	for i, p := range patches {
+		matched := false
+
+		for j := 0; j < len(list); j++ {
+			if !etchMatch(list[j], p, hasTime[i]) {
+				continue
+			}
+			matched = true
+
+			if patch[i].Remove {
+				list = append(list[:j], list[j+1:]...)
+				j--
+				continue
+			}
+
+			list[j] = patchMeasurement(p, list[j].Attrs().Time, hasTime[i])
+		}
+
+		if !matched && !patch[i].Remove {
+			list = append(list, p)
+		}
+	}
+
+	return list, nil
+}
+
+// etchMatch returns true if a measurement is matched by the measurement of a
+// FETCH or iPATCH record, which is the case if the names are equal,
+// and the times are equal if the record contains a time.
+func etchMatch(m, r Measurement, hasTime bool) bool {
+	a, ra := m.Attrs(), r.Attrs()
+	return a.Name == ra.Name && (!hasTime || a.Time.Equal(ra.Time))
+}
+
+// patchMeasurement returns a copy of a patch measurement,
+// with the given time if the patch has no time.
+func patchMeasurement(m Measurement, t time.Time, hasTime bool) Measurement {
+	if hasTime {
+		return m
+	}
+
+	switch v := m.(type) {
+	case *Value:
+		c := *v
+		c.Time = t
+		return &c
+	case *Sum:
+		c := *v
+		c.Time = t
+		return &c
+	case *String:
+		c := *v
+		c.Time = t
+		return &c
+	case *Boolean:
+		c := *v
+		c.Time = t
+		return &c
+	case *Data:
+		c := *v
+		c.Time = t
+		return &c
+	case *ObjectLink:
+		c := *v
+		c.Time = t
+		return &c
+	default:
+		return m
+	}
+}
@@ -0,0 +1,166 @@
+package senml
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var etchResource = []Measurement{
+	NewValue("2001:db8::2/temperature", 23.1, Celsius, time.Unix(1555487588, 0), 0),
+	NewValue("2001:db8::2/humidity", 33.7, RelativeHumidityPercent, time.Unix(1555487588, 0), 0),
+	NewValue("2001:db8::2/humidity", 34.2, RelativeHumidityPercent, time.Unix(1555487598, 0), 0),
+	NewString("2001:db8::2/state", "ok", None, time.Unix(1555487588, 0), 0),
+}
+
+func TestEtchCodecs(t *testing.T) {
+	records := []Record{
+		{BaseName: "2001:db8::2/", Name: "temperature", Unit: "Cel", Value: 23.5},
+		{Name: "humidity", Remove: true},
+		{Name: "state", StringValue: "ok", Time: int64(1555487588)},
+	}
+	tests := map[string]struct {
+		Codec         Codec
+		MediaType     string
+		ContentFormat ContentFormat
+		Expected      string
+	}{
+		"JSON": {
+			EtchJSONCodec(), MediaTypeEtchJSON, ContentFormatEtchJSON,
+			`[{"bn":"2001:db8::2/","n":"temperature","u":"Cel","v":23.5},{"n":"humidity","v":null},{"n":"state","vs":"ok","t":1555487588}]`,
+		},
+		"CBOR": {Codec: EtchCBORCodec(), MediaType: MediaTypeEtchCBOR, ContentFormat: ContentFormatEtchCBOR},
+	}
+
+	for n, test := range tests {
+		if test.Codec.MediaType() != test.MediaType || test.Codec.ContentFormat() != test.ContentFormat {
+			t.Errorf("Media type of %s incorrect, got %s (%v)", n, test.Codec.MediaType(), test.Codec.ContentFormat())
+		}
+		if _, ok := LookupMediaType(test.MediaType); ok {
+			t.Errorf("Codec for %s is registered", n)
+		}
+
+		b, err := test.Codec.Encode(records)
+		if err != nil {
+			t.Errorf("Error encoding %s: %s", n, err)
+			continue
+		}
+		if test.Expected != "" && string(b) != test.Expected {
+			t.Errorf("Encoding %s incorrect, got:\n%s\nexpected:\n%s", n, b, test.Expected)
+		}
+
+		res, err := test.Codec.Decode(b)
+		if err != nil {
+			t.Errorf("Error decoding %s: %s", n, err)
+			continue
+		}
+		for i := range res {
+			if res[i].Name != records[i].Name || res[i].Remove != records[i].Remove {
+				t.Errorf("Decoding %s incorrect, got:\n%#v\nexpected:\n%#v", n, res[i], records[i])
+			}
+		}
+	}
+
+	res, err := DecodeEtchJSONRecords([]byte(`[{"n":"a","vs":null},{"n":"b","v":1},{"n":"c","vlo":null}]`))
+	if err != nil || !res[0].Remove || res[1].Remove || !res[2].Remove {
+		t.Errorf("Decoding of null string value incorrect, got %#v (%v)", res, err)
+	}
+}
+
+func TestApplyFetch(t *testing.T) {
+	tests := map[string]struct {
+		Fetch    string
+		Expected []Measurement
+	}{
+		"Names": {
+			Fetch:    `[{"bn":"2001:db8::2/","n":"humidity"},{"n":"state"},{"n":"foo"}]`,
+			Expected: etchResource[1:],
+		},
+		"Time": {
+			Fetch:    `[{"bn":"2001:db8::2/","n":"humidity","t":1555487598},{"n":"state","t":1555487598}]`,
+			Expected: etchResource[2:3],
+		},
+	}
+
+	for n, test := range tests {
+		fetch, err := DecodeEtchJSONRecords([]byte(test.Fetch))
+		if err != nil {
+			t.Errorf("Error decoding fetch pack for %s: %s", n, err)
+			continue
+		}
+
+		list, err := ApplyFetch(etchResource, fetch)
+		if err != nil {
+			t.Errorf("Error applying fetch for %s: %s", n, err)
+			continue
+		}
+		if !equal(list, test.Expected) {
+			t.Errorf("Fetch result for %s incorrect, got:\n%s\nexpected:\n%s", n, toString(list), toString(test.Expected))
+		}
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	AutoTime = false
+	tests := map[string]struct {
+		Patch    string
+		Expected []Measurement
+	}{
+		"Replace": {
+			Patch: `[{"bn":"2001:db8::2/","n":"temperature","v":24}]`,
+			Expected: []Measurement{
+				NewValue("2001:db8::2/temperature", 24, None, time.Unix(1555487588, 0), 0),
+				etchResource[1], etchResource[2], etchResource[3],
+			},
+		},
+		"Replace with time": {
+			Patch: `[{"bn":"2001:db8::2/","bt":1555487598,"n":"humidity","u":"%RH","v":35}]`,
+			Expected: []Measurement{
+				etchResource[0], etchResource[1],
+				NewValue("2001:db8::2/humidity", 35, RelativeHumidityPercent, time.Unix(1555487598, 0), 0),
+				etchResource[3],
+			},
+		},
+		"Remove": {
+			Patch:    `[{"bn":"2001:db8::2/","n":"humidity","v":null}]`,
+			Expected: []Measurement{etchResource[0], etchResource[3]},
+		},
+		"Remove with time": {
+			Patch:    `[{"bn":"2001:db8::2/","n":"humidity","t":1555487588,"v":null}]`,
+			Expected: []Measurement{etchResource[0], etchResource[2], etchResource[3]},
+		},
+		"Add": {
+			Patch: `[{"bn":"2001:db8::2/","n":"pressure","u":"Pa","v":101325,"t":1555487588},{"n":"foo","v":null}]`,
+			Expected: append(append([]Measurement{}, etchResource...),
+				NewValue("2001:db8::2/pressure", 101325, Pascal, time.Unix(1555487588, 0), 0)),
+		},
+		"Replace unit": {
+			Patch: `[{"bn":"2001:db8::2/","n":"temperature","u":"K","v":297,"t":1555487588}]`,
+			Expected: []Measurement{
+				NewValue("2001:db8::2/temperature", 297, Kelvin, time.Unix(1555487588, 0), 0),
+				etchResource[1], etchResource[2], etchResource[3],
+			},
+		},
+	}
+
+	for n, test := range tests {
+		patch, err := DecodeEtchJSONRecords([]byte(test.Patch))
+		if err != nil {
+			t.Errorf("Error decoding patch for %s: %s", n, err)
+			continue
+		}
+
+		orig := append([]Measurement{}, etchResource...)
+		list, err := ApplyPatch(etchResource, patch)
+		if err != nil {
+			t.Errorf("Error applying patch for %s: %s", n, err)
+			continue
+		}
+		if !equal(list, test.Expected) {
+			t.Errorf("Patch result for %s incorrect, got:\n%s\nexpected:\n%s", n, toString(list), toString(test.Expected))
+		}
+		if !reflect.DeepEqual(orig, etchResource) {
+			t.Errorf("Patch for %s modified the resource", n)
+		}
+	}
+}
@@ -245,6 +245,23 @@ var testVectors = map[string]TestVector{
 			NewData("urn:dev:ow:10e2073a01080063:nfc-reader", []byte("hi \n"), None, time.Time{}, 0),
 		},
 	},
+	"Object Links": {
+		JSON: `[{"bn":"/65/0/0/","n":"0","vlo":"3303:0"},{"n":"1","vlo":"3303:1"}]`,
+		XML: `<sensml xmlns="urn:ietf:params:xml:ns:senml">
+		        <senml bn="/65/0/0/" n="0" vlo="3303:0"></senml>
+		        <senml n="1" vlo="3303:1"></senml>
+		      </sensml>`,
+		CBOR: []byte{
+			0x82,
+			0xa3, 0x21, 0x68, '/', '6', '5', '/', '0', '/', '0', '/', 0x00, 0x61, '0',
+			0x63, 'v', 'l', 'o', 0x66, '3', '3', '0', '3', ':', '0',
+			0xa2, 0x00, 0x61, '1', 0x63, 'v', 'l', 'o', 0x66, '3', '3', '0', '3', ':', '1',
+		},
+		Result: []Measurement{
+			NewObjectLink("/65/0/0/0", "3303:0", None, time.Time{}, 0),
+			NewObjectLink("/65/0/0/1", "3303:1", None, time.Time{}, 0),
+		},
+	},
 }
 
 var regexpWhitespace = regexp.MustCompile(`\s`)
@@ -2,4 +2,9 @@ module github.com/silkeh/senml
 
 go 1.16
 
-require github.com/ugorji/go/codec v1.2.4
+require (
+	github.com/eclipse/paho.mqtt.golang v1.4.2
+	github.com/plgd-dev/go-coap/v2 v2.4.0
+	github.com/ugorji/go/codec v1.2.4
+	gopkg.in/yaml.v3 v3.0.1
+)
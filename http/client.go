@@ -0,0 +1,279 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/silkeh/senml"
+)
+
+// Default values of the Client.
+const (
+	DefaultMaxRetries = 3
+	DefaultBackoff    = 100 * time.Millisecond
+	DefaultMaxBackoff = 10 * time.Second
+)
+
+// Client posts packs to an ingestion endpoint, such as an IngestHandler.
+// Requests are retried with exponential backoff on temporary network errors,
+// server errors (5xx) and Too Many Requests (429) responses. If the server does not support the media type of the
+// client, another media type listed in the Accept-Post header of the
+// response is used for this and later requests.
+type Client struct {
+	// URL is the URL of the ingestion endpoint.
+	URL string
+
+	// HTTPClient is used for sending requests.
+	// http.DefaultClient is used if this is nil.
+	HTTPClient *http.Client
+
+	// MediaType is the media type used for encoding packs.
+	// DefaultMediaType is used if this is empty.
+	MediaType string
+
+	// Compress enables gzip compression of the request body.
+	Compress bool
+
+	// MaxRetries is the maximum number of retries of a request.
+	MaxRetries int
+
+	// Backoff is the delay before the first retry, which is doubled for
+	// each following retry up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// MaxPackSize is the maximum size of an encoded pack in bytes.
+	// Larger packs are split and posted in multiple requests.
+	// Packs are not split if this is zero.
+	MaxPackSize int
+
+	mutex sync.Mutex
+}
+
+// NewClient returns a new Client for the given URL with default settings.
+func NewClient(url string) *Client {
+	return &Client{
+		URL:        url,
+		MediaType:  DefaultMediaType,
+		MaxRetries: DefaultMaxRetries,
+		Backoff:    DefaultBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+	}
+}
+
+// Post posts a list of measurements to the endpoint.
+// The list is split into multiple packs if it exceeds MaxPackSize.
+// A Problem is returned if the server responds with an error.
+func (c *Client) Post(ctx context.Context, list []senml.Measurement) error {
+	return c.post(ctx, list, true)
+}
+
+// errUnsupportedMediaType is returned by do if the media type of the client was changed.
+var errUnsupportedMediaType = errors.New("unsupported media type")
+
+// post encodes and posts a list of measurements, splitting it if required.
+// The list is posted again if the media type of the client was changed and retry is true.
+func (c *Client) post(ctx context.Context, list []senml.Measurement, retry bool) error {
+	codec, err := c.codec()
+	if err != nil {
+		return err
+	}
+
+	b, err := codec.Encode(senml.Encode(list))
+	if err != nil {
+		return err
+	}
+
+	if c.MaxPackSize > 0 && len(b) > c.MaxPackSize {
+		if len(list) < 2 {
+			return fmt.Errorf("pack of %d bytes exceeds maximum size of %d bytes", len(b), c.MaxPackSize)
+		}
+		if err := c.post(ctx, list[:len(list)/2], retry); err != nil {
+			return err
+		}
+		return c.post(ctx, list[len(list)/2:], retry)
+	}
+
+	err = c.send(ctx, codec.MediaType(), b)
+	if errors.Is(err, errUnsupportedMediaType) {
+		if retry {
+			return c.post(ctx, list, false)
+		}
+		return NewProblem(http.StatusUnsupportedMediaType, "unsupported content type "+codec.MediaType())
+	}
+	return err
+}
+
+// codec returns the codec for the media type of the client.
+func (c *Client) codec() (senml.Codec, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	mt := c.MediaType
+	if mt == "" {
+		mt = DefaultMediaType
+	}
+
+	codec, ok := senml.LookupMediaType(mt)
+	if !ok {
+		return nil, fmt.Errorf("unsupported media type %q", mt)
+	}
+	return codec, nil
+}
+
+// send sends an encoded pack, retrying on server and network errors.
+func (c *Client) send(ctx context.Context, mediaType string, b []byte) error {
+	body, encoding, err := c.compress(b)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.URL, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", mediaType)
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+
+	backoff := c.Backoff
+	for i := 0; ; i++ {
+		err := c.do(req, body)
+		if err == nil {
+			return nil
+		}
+
+		if !retryable(err) || i >= c.MaxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if c.MaxBackoff > 0 && backoff > c.MaxBackoff {
+			backoff = c.MaxBackoff
+		}
+	}
+}
+
+// compress returns the compressed request body and content encoding if compression is enabled.
+func (c *Client) compress(b []byte) ([]byte, string, error) {
+	if !c.Compress {
+		return b, "", nil
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		return nil, "", err
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "gzip", nil
+}
+
+// retryable returns true if a request that failed with the given error may succeed when retried.
+// This is the case for server errors (5xx), Too Many Requests responses,
+// timeouts, temporary network errors and connections closed by the server.
+func retryable(err error) bool {
+	if errors.Is(err, errUnsupportedMediaType) {
+		return false
+	}
+
+	var p *Problem
+	if errors.As(err, &p) {
+		return p.Status >= 500 || p.Status == http.StatusTooManyRequests
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var ne net.Error
+	return errors.As(err, &ne) && (ne.Timeout() || ne.Temporary())
+}
+
+// do performs a single attempt of a request with the given body.
+// Errors returned by do are either a Problem, errUnsupportedMediaType or a network error.
+func (c *Client) do(req *http.Request, b []byte) error {
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	req.ContentLength = int64(len(b))
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil
+	}
+
+	if resp.StatusCode == http.StatusUnsupportedMediaType && c.negotiate(req.Header.Get("Content-Type"), resp.Header.Get("Accept-Post")) {
+		return errUnsupportedMediaType
+	}
+
+	return responseProblem(resp)
+}
+
+// negotiate changes the media type of the client to a supported type in an Accept-Post header.
+// It returns false if no other supported media type is found.
+func (c *Client) negotiate(current, acceptPost string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, s := range strings.Split(acceptPost, ",") {
+		mt, _, err := mime.ParseMediaType(s)
+		if err != nil || mt == current {
+			continue
+		}
+		if _, ok := senml.LookupMediaType(mt); ok {
+			c.MediaType = mt
+			return true
+		}
+	}
+
+	return false
+}
+
+// responseProblem returns the Problem in an error response.
+// A Problem is created from the status code if the response contains no problem details.
+func responseProblem(resp *http.Response) *Problem {
+	b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, DefaultMaxBodySize))
+
+	if mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mt == ProblemMediaType {
+		p := new(Problem)
+		if err := json.Unmarshal(b, p); err == nil {
+			p.Status = resp.StatusCode
+			return p
+		}
+	}
+
+	return NewProblem(resp.StatusCode, strings.TrimSpace(string(b)))
+}
@@ -0,0 +1,238 @@
+package http
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/silkeh/senml"
+	"github.com/silkeh/senml/internal/senmltest"
+)
+
+// testServer is an ingestion server that records the received requests.
+type testServer struct {
+	*httptest.Server
+	mutex        sync.Mutex
+	failures     int
+	mediaTypes   []string
+	encodings    []string
+	measurements []senml.Measurement
+}
+
+func newTestServer(failures int, mediaType string) *testServer {
+	s := &testServer{failures: failures}
+	ingest := NewIngestHandler(func(r *http.Request, list []senml.Measurement) error {
+		s.measurements = append(s.measurements, list...)
+		return nil
+	})
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		s.mediaTypes = append(s.mediaTypes, r.Header.Get("Content-Type"))
+		s.encodings = append(s.encodings, r.Header.Get("Content-Encoding"))
+		if s.failures > 0 {
+			s.failures--
+			writeProblem(w, NewProblem(http.StatusServiceUnavailable, ""))
+			return
+		}
+		if mediaType != "" && r.Header.Get("Content-Type") != mediaType {
+			w.Header().Set("Accept-Post", "application/json, "+mediaType)
+			writeProblem(w, NewProblem(http.StatusUnsupportedMediaType, ""))
+			return
+		}
+		ingest.ServeHTTP(w, r)
+	}))
+
+	return s
+}
+
+func newTestClient(url string) *Client {
+	c := NewClient(url)
+	c.Backoff = time.Millisecond
+	return c
+}
+
+func TestClient(t *testing.T) {
+	senml.AutoTime = false
+	for _, mt := range []string{senml.MediaTypeJSON, senml.MediaTypeCBOR, senml.MediaTypeXML} {
+		for _, compress := range []bool{false, true} {
+			s := newTestServer(2, "")
+			c := newTestClient(s.URL)
+			c.MediaType = mt
+			c.Compress = compress
+
+			if err := c.Post(context.Background(), testMeasurements); err != nil {
+				t.Errorf("Error posting %s (compressed: %v): %s", mt, compress, err)
+			}
+			if !senmltest.Equal(s.measurements, testMeasurements) {
+				t.Errorf("Received measurements for %s (compressed: %v) incorrect, got:\n%v\nexpected:\n%v",
+					mt, compress, s.measurements, testMeasurements)
+			}
+			if len(s.mediaTypes) != 3 || s.mediaTypes[2] != mt {
+				t.Errorf("Requests for %s incorrect, got media types %v", mt, s.mediaTypes)
+			}
+			if compress && s.encodings[2] != "gzip" {
+				t.Errorf("Request for %s not compressed, got encoding %q", mt, s.encodings[2])
+			}
+			s.Close()
+		}
+	}
+}
+
+func TestClientNegotiate(t *testing.T) {
+	s := newTestServer(0, senml.MediaTypeCBOR)
+	defer s.Close()
+
+	c := newTestClient(s.URL)
+	for i := 0; i < 2; i++ {
+		if err := c.Post(context.Background(), testMeasurements); err != nil {
+			t.Errorf("Error posting: %s", err)
+		}
+	}
+
+	exp := []string{senml.MediaTypeJSON, senml.MediaTypeCBOR, senml.MediaTypeCBOR}
+	if len(s.mediaTypes) != len(exp) || s.mediaTypes[0] != exp[0] || s.mediaTypes[2] != exp[2] {
+		t.Errorf("Requested media types incorrect, got %v, expected %v", s.mediaTypes, exp)
+	}
+	if c.MediaType != senml.MediaTypeCBOR {
+		t.Errorf("Media type of client not changed, got %q", c.MediaType)
+	}
+}
+
+func TestClientSplit(t *testing.T) {
+	senml.AutoTime = false
+	s := newTestServer(0, "")
+	defer s.Close()
+
+	var list []senml.Measurement
+	for i := 0; i < 10; i++ {
+		list = append(list, senml.NewValue("sensor:temperature", float64(i), senml.Celsius, time.Unix(1555487588+int64(i), 0), 0))
+	}
+
+	c := newTestClient(s.URL)
+	c.MaxPackSize = 100
+	if err := c.Post(context.Background(), list); err != nil {
+		t.Errorf("Error posting: %s", err)
+	}
+	if len(s.mediaTypes) < 2 {
+		t.Errorf("Pack not split, got %v requests", len(s.mediaTypes))
+	}
+	if !senmltest.Equal(s.measurements, list) {
+		t.Errorf("Received measurements incorrect, got:\n%v\nexpected:\n%v", s.measurements, list)
+	}
+
+	c.MaxPackSize = 10
+	if err := c.Post(context.Background(), list); err == nil {
+		t.Errorf("Expected error for pack exceeding maximum size")
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	s := newTestServer(10, "")
+	c := newTestClient(s.URL)
+	err := c.Post(context.Background(), testMeasurements)
+	if p, ok := err.(*Problem); !ok || p.Status != http.StatusServiceUnavailable {
+		t.Errorf("Expected Service Unavailable problem, got: %v", err)
+	}
+	if n := len(s.mediaTypes); n != c.MaxRetries+1 {
+		t.Errorf("Expected %v requests, got %v", c.MaxRetries+1, n)
+	}
+	s.Close()
+
+	s = newTestServer(0, "application/unsupported")
+	c = newTestClient(s.URL)
+	err = c.Post(context.Background(), testMeasurements)
+	if p, ok := err.(*Problem); !ok || p.Status != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected Unsupported Media Type problem, got: %v", err)
+	}
+	if n := len(s.mediaTypes); n != 1 {
+		t.Errorf("Expected 1 request, got %v", n)
+	}
+	s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := newTestClient(s.URL).Post(ctx, testMeasurements); err == nil {
+		t.Errorf("Expected error for closed server")
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	tests := map[int]int{
+		http.StatusTooManyRequests:       DefaultMaxRetries + 1,
+		http.StatusBadGateway:            DefaultMaxRetries + 1,
+		http.StatusBadRequest:            1,
+		http.StatusRequestEntityTooLarge: 1,
+	}
+
+	for status, exp := range tests {
+		var requests int
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			writeProblem(w, NewProblem(status, ""))
+		}))
+
+		err := newTestClient(s.URL).Post(context.Background(), testMeasurements)
+		if p, ok := err.(*Problem); !ok || p.Status != status {
+			t.Errorf("Expected problem with status %v, got: %v", status, err)
+		}
+		if requests != exp {
+			t.Errorf("Expected %v requests for status %v, got %v", exp, status, requests)
+		}
+		s.Close()
+	}
+
+	for _, url := range []string{"http://[::1", "ftp://localhost/"} {
+		c := newTestClient(url)
+		c.Backoff = time.Hour
+		if err := c.Post(context.Background(), testMeasurements); err == nil {
+			t.Errorf("Expected error for URL %q", url)
+		}
+	}
+}
+
+// roundTripFunc is an http.RoundTripper using a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// timeoutError is a network error that timed out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClientRetryTransport(t *testing.T) {
+	tests := map[string]struct {
+		Err      error
+		Requests int
+	}{
+		"certificate": {x509.UnknownAuthorityError{}, 1},
+		"timeout":     {timeoutError{}, DefaultMaxRetries + 1},
+		"reset":       {syscall.ECONNRESET, DefaultMaxRetries + 1},
+	}
+
+	for n, test := range tests {
+		var requests int
+		c := newTestClient("http://localhost/")
+		c.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			requests++
+			return nil, test.Err
+		})}
+
+		if err := c.Post(context.Background(), testMeasurements); err == nil {
+			t.Errorf("Expected error for %s", n)
+		}
+		if requests != test.Requests {
+			t.Errorf("Expected %v requests for %s, got %v", test.Requests, n, requests)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Package http provides HTTP handlers for serving and receiving SenML packs.
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/silkeh/senml"
+)
+
+// Source returns the measurements to serve for a request.
+type Source func(r *http.Request) ([]senml.Measurement, error)
+
+// Handler serves the measurements from a Source.
+// The format of the response is negotiated using the Accept header of the request,
+// and any registered SenML codec can be used.
+// A Not Acceptable response is returned if none of the accepted formats is supported.
+type Handler struct {
+	Source Source
+
+	// ErrorLog is used for logging errors of the source and the encoder.
+	// The standard logger is used if this is nil.
+	ErrorLog *log.Logger
+}
+
+// NewHandler returns a new Handler serving the measurements from the given source.
+func NewHandler(source Source) *Handler {
+	return &Handler{Source: source}
+}
+
+// ServeHTTP serves the measurements from the source.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodHead}, ", "))
+		writeProblem(w, NewProblem(http.StatusMethodNotAllowed, ""))
+		return
+	}
+
+	w.Header().Add("Vary", "Accept")
+	c, ok := negotiate(r.Header.Get("Accept"))
+	if !ok {
+		writeProblem(w, NewProblem(http.StatusNotAcceptable,
+			"supported media types: "+strings.Join(mediaTypes(), ", ")))
+		return
+	}
+
+	list, err := h.Source(r)
+	if err != nil {
+		writeError(w, err, h.ErrorLog)
+		return
+	}
+
+	b, err := c.Encode(senml.Encode(list))
+	if err != nil {
+		writeError(w, err, h.ErrorLog)
+		return
+	}
+
+	w.Header().Set("Content-Type", c.MediaType())
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(b)
+	}
+}
+
+// mediaTypes returns the media types of all registered codecs.
+func mediaTypes() []string {
+	names := senml.Codecs()
+	types := make([]string, 0, len(names))
+	for _, n := range names {
+		if c, ok := senml.LookupCodec(n); ok {
+			types = append(types, c.MediaType())
+		}
+	}
+	return types
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/silkeh/senml"
+	"github.com/silkeh/senml/internal/senmltest"
+)
+
+var testMeasurements = senmltest.Measurements()
+
+func testSource(r *http.Request) ([]senml.Measurement, error) {
+	return testMeasurements, nil
+}
+
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) *Problem {
+	if ct := rec.Header().Get("Content-Type"); ct != ProblemMediaType {
+		t.Errorf("Content-Type of problem incorrect, got %q", ct)
+	}
+
+	p := new(Problem)
+	if err := json.Unmarshal(rec.Body.Bytes(), p); err != nil {
+		t.Errorf("Error decoding problem: %s", err)
+	}
+	if p.Status != rec.Code {
+		t.Errorf("Status of problem incorrect, got %v, expected %v", p.Status, rec.Code)
+	}
+	return p
+}
+
+func TestHandler(t *testing.T) {
+	senml.AutoTime = false
+	tests := map[string]string{
+		"":                                       senml.MediaTypeJSON,
+		"*/*":                                    senml.MediaTypeJSON,
+		"application/*":                          senml.MediaTypeJSON,
+		senml.MediaTypeJSON:                      senml.MediaTypeJSON,
+		senml.MediaTypeCBOR:                      senml.MediaTypeCBOR,
+		senml.MediaTypeXML:                       senml.MediaTypeXML,
+		senml.MediaTypeSensMLCBOR:                senml.MediaTypeSensMLCBOR,
+		"text/html, application/senml+xml;q=0.9": senml.MediaTypeXML,
+		"application/senml+json;q=0.5, */*;q=0.1":              senml.MediaTypeJSON,
+		"application/senml+json;q=0.5, application/senml+cbor": senml.MediaTypeCBOR,
+		"application/senml+cbor;q=0, */*;q=0.1":                senml.MediaTypeJSON,
+		"application/senml+json;q=0, */*":                      senml.MediaTypeCBOR,
+		"application/senml+json;q=0, application/*":            senml.MediaTypeCBOR,
+	}
+
+	h := NewHandler(testSource)
+	for accept, exp := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Status for %q incorrect, got %v", accept, rec.Code)
+			continue
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != exp {
+			t.Errorf("Content-Type for %q incorrect, got %q, expected %q", accept, ct, exp)
+			continue
+		}
+
+		list, err := senml.DecodeMediaType(exp, rec.Body.Bytes())
+		if err != nil {
+			t.Errorf("Error decoding response for %q: %s", accept, err)
+			continue
+		}
+		if !senmltest.Equal(list, testMeasurements) {
+			t.Errorf("Response for %q incorrect, got:\n%v\nexpected:\n%v", accept, list, testMeasurements)
+		}
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	tests := map[string]struct {
+		Method, Accept string
+		Source         Source
+		Status         int
+	}{
+		"Not acceptable": {
+			http.MethodGet, "application/json, text/*", testSource, http.StatusNotAcceptable,
+		},
+		"Method not allowed": {
+			http.MethodPost, "", testSource, http.StatusMethodNotAllowed,
+		},
+		"Source error": {
+			http.MethodGet, "", func(*http.Request) ([]senml.Measurement, error) {
+				return nil, errors.New("test")
+			}, http.StatusInternalServerError,
+		},
+		"Source problem": {
+			http.MethodGet, "", func(*http.Request) ([]senml.Measurement, error) {
+				return nil, NewProblem(http.StatusNotFound, "no such sensor")
+			}, http.StatusNotFound,
+		},
+	}
+
+	for n, test := range tests {
+		req := httptest.NewRequest(test.Method, "/", nil)
+		req.Header.Set("Accept", test.Accept)
+		rec := httptest.NewRecorder()
+		h := NewHandler(test.Source)
+		h.ErrorLog = log.New(ioutil.Discard, "", 0)
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != test.Status {
+			t.Errorf("Status for %s incorrect, got %v, expected %v", n, rec.Code, test.Status)
+		}
+		decodeProblem(t, rec)
+	}
+}
+
+func TestHandlerInternalError(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewHandler(func(*http.Request) ([]senml.Measurement, error) {
+		return nil, errors.New("database password rejected")
+	})
+	h.ErrorLog = log.New(&buf, "", 0)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if p := decodeProblem(t, rec); p.Status != http.StatusInternalServerError || p.Detail != "" {
+		t.Errorf("Problem for internal error incorrect: %#v", p)
+	}
+	if !strings.Contains(buf.String(), "database password rejected") {
+		t.Errorf("Internal error not logged, got: %q", buf.String())
+	}
+}
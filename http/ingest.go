@@ -0,0 +1,112 @@
+package http
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/silkeh/senml"
+)
+
+// DefaultMaxBodySize is the default maximum size of a request body in bytes.
+const DefaultMaxBodySize = 1 << 20
+
+// Receiver processes the measurements received in a request.
+type Receiver func(r *http.Request, list []senml.Measurement) error
+
+// IngestHandler decodes packs sent in POST requests, and passes the measurements to a Receiver.
+// The format of the request body is determined by the Content-Type header,
+// and any registered SenML codec can be used. Supported media types are listed
+// in the Accept-Post header of an Unsupported Media Type response.
+// Bodies compressed using gzip are accepted.
+// A No Content response is returned if the Receiver returns no error.
+type IngestHandler struct {
+	Receiver Receiver
+
+	// MaxBodySize is the maximum size of a request body in bytes.
+	// DefaultMaxBodySize is used if this is zero.
+	MaxBodySize int64
+
+	// ErrorLog is used for logging errors of the receiver.
+	// The standard logger is used if this is nil.
+	ErrorLog *log.Logger
+}
+
+// NewIngestHandler returns a new IngestHandler passing measurements to the given receiver.
+func NewIngestHandler(receiver Receiver) *IngestHandler {
+	return &IngestHandler{Receiver: receiver, MaxBodySize: DefaultMaxBodySize}
+}
+
+// ServeHTTP decodes the measurements in the request and passes them to the receiver.
+func (h *IngestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeProblem(w, NewProblem(http.StatusMethodNotAllowed, ""))
+		return
+	}
+
+	c, ok := senml.LookupMediaType(r.Header.Get("Content-Type"))
+	if !ok {
+		w.Header().Set("Accept-Post", strings.Join(mediaTypes(), ", "))
+		writeProblem(w, NewProblem(http.StatusUnsupportedMediaType,
+			"unsupported content type "+r.Header.Get("Content-Type")))
+		return
+	}
+
+	var body io.Reader = r.Body
+	switch r.Header.Get("Content-Encoding") {
+	case "", "identity":
+	case "gzip":
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			writeProblem(w, NewProblem(http.StatusBadRequest, err.Error()))
+			return
+		}
+		defer gz.Close()
+		body = gz
+	default:
+		w.Header().Set("Accept-Encoding", "gzip")
+		writeProblem(w, NewProblem(http.StatusUnsupportedMediaType,
+			"unsupported content encoding "+r.Header.Get("Content-Encoding")))
+		return
+	}
+
+	max := h.MaxBodySize
+	if max == 0 {
+		max = DefaultMaxBodySize
+	}
+
+	b, err := ioutil.ReadAll(io.LimitReader(body, max+1))
+	if err != nil {
+		writeProblem(w, NewProblem(http.StatusBadRequest, err.Error()))
+		return
+	}
+	if int64(len(b)) > max {
+		writeProblem(w, NewProblem(http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("request body exceeds %d bytes", max)))
+		return
+	}
+
+	records, err := c.Decode(b)
+	if err != nil {
+		writeProblem(w, NewProblem(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	list, err := senml.Decode(records)
+	if err != nil {
+		writeProblem(w, NewProblem(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := h.Receiver(r, list); err != nil {
+		writeError(w, err, h.ErrorLog)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
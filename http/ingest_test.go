@@ -0,0 +1,92 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/silkeh/senml"
+	"github.com/silkeh/senml/internal/senmltest"
+)
+
+func TestIngestHandler(t *testing.T) {
+	senml.AutoTime = false
+	for _, mt := range []string{
+		senml.MediaTypeJSON, senml.MediaTypeCBOR, senml.MediaTypeXML,
+		senml.MediaTypeSensMLJSON, senml.MediaTypeSensMLCBOR, senml.MediaTypeSensMLXML,
+		senml.MediaTypeJSON + "; charset=utf-8",
+	} {
+		b, err := senml.EncodeMediaType(mt, testMeasurements)
+		if err != nil {
+			t.Fatalf("Error encoding %s: %s", mt, err)
+		}
+
+		var received []senml.Measurement
+		h := NewIngestHandler(func(r *http.Request, list []senml.Measurement) error {
+			received = list
+			return nil
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(b))
+		req.Header.Set("Content-Type", mt)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("Status for %s incorrect, got %v: %s", mt, rec.Code, rec.Body)
+		}
+		if !senmltest.Equal(received, testMeasurements) {
+			t.Errorf("Received measurements for %s incorrect, got:\n%v\nexpected:\n%v", mt, received, testMeasurements)
+		}
+	}
+}
+
+func TestIngestHandlerErrors(t *testing.T) {
+	receiver := func(r *http.Request, list []senml.Measurement) error {
+		if list[0].Attrs().Name == "forbidden" {
+			return NewProblem(http.StatusForbidden, "")
+		}
+		return nil
+	}
+
+	tests := map[string]struct {
+		Method, ContentType, Body string
+		Status                    int
+	}{
+		"Method not allowed": {
+			http.MethodGet, senml.MediaTypeJSON, "", http.StatusMethodNotAllowed,
+		},
+		"Unsupported media type": {
+			http.MethodPost, "application/json", `[{"n":"a","v":1}]`, http.StatusUnsupportedMediaType,
+		},
+		"Invalid body": {
+			http.MethodPost, senml.MediaTypeJSON, `{"n":"a","v":1}`, http.StatusBadRequest,
+		},
+		"Invalid record": {
+			http.MethodPost, senml.MediaTypeJSON, `[{"n":"a"}]`, http.StatusBadRequest,
+		},
+		"Too large": {
+			http.MethodPost, senml.MediaTypeJSON, `[{"n":"a","vs":"` + strings.Repeat("a", 64) + `"}]`,
+			http.StatusRequestEntityTooLarge,
+		},
+		"Receiver problem": {
+			http.MethodPost, senml.MediaTypeJSON, `[{"n":"forbidden","v":1}]`, http.StatusForbidden,
+		},
+	}
+
+	h := NewIngestHandler(receiver)
+	h.MaxBodySize = 64
+	for n, test := range tests {
+		req := httptest.NewRequest(test.Method, "/", strings.NewReader(test.Body))
+		req.Header.Set("Content-Type", test.ContentType)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != test.Status {
+			t.Errorf("Status for %s incorrect, got %v, expected %v", n, rec.Code, test.Status)
+		}
+		decodeProblem(t, rec)
+	}
+}
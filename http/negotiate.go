@@ -0,0 +1,95 @@
+package http
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/silkeh/senml"
+)
+
+// DefaultMediaType is the media type used when the client accepts any format.
+const DefaultMediaType = senml.MediaTypeJSON
+
+// acceptRange represents a media range in an Accept header.
+type acceptRange struct {
+	MediaType string
+	Quality   float64
+}
+
+// parseAccept returns the media ranges in an Accept header, ordered by preference,
+// and the media ranges that are refused with a quality of zero.
+// Ranges that cannot be parsed are omitted.
+func parseAccept(accept string) (ranges []acceptRange, refused map[string]bool) {
+	refused = make(map[string]bool)
+	for _, s := range strings.Split(accept, ",") {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+
+		mt, params, err := mime.ParseMediaType(s)
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		if q == 0 {
+			refused[mt] = true
+			continue
+		}
+
+		ranges = append(ranges, acceptRange{MediaType: mt, Quality: q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].Quality > ranges[j].Quality
+	})
+
+	return
+}
+
+// negotiate returns the codec for the most preferred media type in an Accept header.
+// The codec for DefaultMediaType is returned if the header is empty.
+// For a range that allows any type, the codec for DefaultMediaType is returned
+// unless it is refused, in which case the first registered codec that is not refused is used.
+func negotiate(accept string) (senml.Codec, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return senml.LookupMediaType(DefaultMediaType)
+	}
+
+	ranges, refused := parseAccept(accept)
+	for _, r := range ranges {
+		switch r.MediaType {
+		case "*/*", "application/*":
+			return anyCodec(refused)
+		}
+		if c, ok := senml.LookupMediaType(r.MediaType); ok {
+			return c, true
+		}
+	}
+
+	return nil, false
+}
+
+// anyCodec returns the codec for DefaultMediaType, or the first registered codec,
+// of which the media type is not refused.
+func anyCodec(refused map[string]bool) (senml.Codec, bool) {
+	if !refused[DefaultMediaType] {
+		if c, ok := senml.LookupMediaType(DefaultMediaType); ok {
+			return c, true
+		}
+	}
+
+	for _, n := range senml.Codecs() {
+		if c, ok := senml.LookupCodec(n); ok && !refused[c.MediaType()] {
+			return c, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// ProblemMediaType is the media type of problem details, as defined in RFC 7807.
+const ProblemMediaType = "application/problem+json"
+
+// Problem represents the details of an error response, as defined in RFC 7807.
+// It implements error, and can be returned by a Source or Receiver
+// to control the response.
+type Problem struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// NewProblem returns a new Problem with the given status code and detail.
+// The title is set to the text of the status code.
+func NewProblem(status int, detail string) *Problem {
+	return &Problem{Title: http.StatusText(status), Status: status, Detail: detail}
+}
+
+// Error returns the title and detail of the problem.
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return p.Title
+	}
+	return p.Title + ": " + p.Detail
+}
+
+// writeError writes a problem response for an error.
+// Errors other than Problem result in an Internal Server Error without details,
+// and are logged to the given logger, or the standard logger if it is nil.
+func writeError(w http.ResponseWriter, err error, logger *log.Logger) {
+	var p *Problem
+	if !errors.As(err, &p) {
+		logf(logger, "senml/http: internal server error: %s", err)
+		p = NewProblem(http.StatusInternalServerError, "")
+	}
+	writeProblem(w, p)
+}
+
+// writeProblem writes a problem response.
+func writeProblem(w http.ResponseWriter, p *Problem) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ProblemMediaType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	w.Write(b)
+}
+
+// logf logs a message to the given logger, or the standard logger if it is nil.
+func logf(logger *log.Logger, format string, args ...interface{}) {
+	if logger != nil {
+		logger.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
@@ -0,0 +1,39 @@
+// Package senmltest provides helpers for the tests of the subpackages.
+package senmltest
+
+import (
+	"strings"
+	"time"
+
+	"github.com/silkeh/senml"
+)
+
+// Measurements returns a list of a temperature and a humidity measurement.
+func Measurements() []senml.Measurement {
+	return []senml.Measurement{
+		senml.NewValue("sensor:temperature", 23.5, senml.Celsius, time.Unix(1555487588, 0), 0),
+		senml.NewValue("sensor:humidity", 33.7, senml.RelativeHumidityPercent, time.Unix(1555487588, 0), 0),
+	}
+}
+
+// Equal returns true if both lists contain equal measurements in the same order.
+func Equal(a, b []senml.Measurement) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the measurements formatted in English, one per line.
+func String(list []senml.Measurement) string {
+	var b strings.Builder
+	for _, m := range list {
+		b.WriteString(senml.Format(m, senml.English) + "\n")
+	}
+	return b.String()
+}
@@ -2,17 +2,27 @@ package senml
 
 import "encoding/json"
 
-// EncodeJSON encodes a list of measurements into JSON.
+// EncodeJSON encodes a list of measurements into JSON using the registered "json" codec.
 func EncodeJSON(list []Measurement) ([]byte, error) {
-	return json.Marshal(Encode(list))
+	return encodeCodec("json", list)
 }
 
-// DecodeJSON decodes a list of measurements from JSON.
+// DecodeJSON decodes a list of measurements from JSON using the registered "json" codec.
 func DecodeJSON(j []byte) ([]Measurement, error) {
+	return decodeCodec("json", j)
+}
+
+// EncodeJSONRecords encodes a list of records into JSON.
+func EncodeJSONRecords(records []Record) ([]byte, error) {
+	return json.Marshal(records)
+}
+
+// DecodeJSONRecords decodes a list of records from JSON.
+func DecodeJSONRecords(j []byte) ([]Record, error) {
 	obj := make([]Record, 0)
 	err := json.Unmarshal(j, &obj)
 	if err != nil {
 		return nil, err
 	}
-	return Decode(obj)
+	return obj, nil
 }
@@ -0,0 +1,283 @@
+package lwm2m
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/silkeh/senml"
+	"github.com/ugorji/go/codec"
+)
+
+var cbor = codec.CborHandle{BasicHandle: codec.BasicHandle{EncodeOptions: codec.EncodeOptions{Canonical: true}}}
+
+// CBORCodec returns a senml.Codec for LwM2M CBOR.
+func CBORCodec() senml.Codec {
+	return cborCodec{}
+}
+
+// Register registers the LwM2M CBOR codec as "lwm2m+cbor" in the SenML codec registry.
+// This makes the format available for content negotiation, eg: by the http and coap packages,
+// which is not done by default as it cannot represent all SenML packs.
+// Calling Register again has no effect.
+func Register() error {
+	if c, ok := senml.LookupCodec("lwm2m+cbor"); ok && c == CBORCodec() {
+		return nil
+	}
+	return senml.RegisterCodec("lwm2m+cbor", CBORCodec())
+}
+
+// cborCodec implements senml.Codec for LwM2M CBOR.
+type cborCodec struct{}
+
+// Encode encodes a list of records into LwM2M CBOR.
+func (cborCodec) Encode(records []senml.Record) ([]byte, error) {
+	list, err := senml.Decode(records)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeCBOR(list)
+}
+
+// Decode decodes a list of records from LwM2M CBOR.
+func (cborCodec) Decode(b []byte) ([]senml.Record, error) {
+	return cborRecords(b)
+}
+
+// MediaType returns the media type of LwM2M CBOR.
+func (cborCodec) MediaType() string {
+	return MediaTypeCBOR
+}
+
+// ContentFormat returns the CoAP Content-Format of LwM2M CBOR.
+func (cborCodec) ContentFormat() senml.ContentFormat {
+	return ContentFormatCBOR
+}
+
+// EncodeCBOR encodes a list of measurements into LwM2M CBOR.
+// The names of the measurements must be paths of resources or resource instances,
+// which are encoded as nested maps with the IDs of the path as keys.
+// Numeric values are encoded using the type and unit of registered resources,
+// or as a float otherwise.
+func EncodeCBOR(list []senml.Measurement) ([]byte, error) {
+	root := make(map[uint64]interface{})
+	for _, m := range list {
+		p, err := MeasurementPath(m)
+		if err != nil {
+			return nil, err
+		}
+		if p.Level() < LevelResource {
+			return nil, fmt.Errorf("path %s is not a resource", p)
+		}
+
+		v, err := measurementValue(p, m)
+		if err != nil {
+			return nil, err
+		}
+		if l, ok := v.(ObjectLink); ok {
+			v = l.String()
+		}
+
+		n := root
+		for _, id := range p[:len(p)-1] {
+			switch c := n[uint64(id)].(type) {
+			case nil:
+				c = make(map[uint64]interface{})
+				n[uint64(id)] = c
+				n = c.(map[uint64]interface{})
+			case map[uint64]interface{}:
+				n = c
+			default:
+				return nil, fmt.Errorf("resource %s: resource instance of a single instance resource", p)
+			}
+		}
+
+		id := uint64(p[len(p)-1])
+		if _, ok := n[id]; ok {
+			return nil, fmt.Errorf("resource %s: duplicate value", p)
+		}
+		n[id] = v
+	}
+
+	var b []byte
+	err := codec.NewEncoderBytes(&b, &cbor).Encode(root)
+	return b, err
+}
+
+// DecodeCBOR decodes a list of measurements from LwM2M CBOR.
+// The keys of the nested maps are the IDs of the path of a value,
+// or arrays of IDs for multiple levels of the path.
+// Values are decoded using the type and unit of registered resources.
+// Resources with empty values are skipped, as these cannot be represented in SenML.
+func DecodeCBOR(b []byte) ([]senml.Measurement, error) {
+	records, err := cborRecords(b)
+	if err != nil {
+		return nil, err
+	}
+	return senml.Decode(records)
+}
+
+// cborRecords decodes the records in LwM2M CBOR.
+func cborRecords(b []byte) ([]senml.Record, error) {
+	records, n, err := cborMapRecords(Path{}, b)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(b) {
+		return nil, fmt.Errorf("unexpected data after LwM2M CBOR map")
+	}
+	return records, nil
+}
+
+// cborMapRecords returns the records of the values in the map at the start of b,
+// below the given path. It also returns the encoded length of the map.
+func cborMapRecords(p Path, b []byte) ([]senml.Record, int, error) {
+	major, entries, pos, err := cborHead(b)
+	if err != nil {
+		return nil, 0, err
+	}
+	if major != 5 {
+		return nil, 0, fmt.Errorf("expected map below %s, got CBOR major type %d", p, major)
+	}
+
+	var records []senml.Record
+	for i := uint64(0); entries < 0 || i < uint64(entries); i++ {
+		if entries < 0 && pos < len(b) && b[pos] == 0xff {
+			pos++
+			break
+		}
+
+		kp, n, err := cborKeyPath(p, b[pos:])
+		if err != nil {
+			return nil, 0, err
+		}
+		pos += n
+		if pos >= len(b) {
+			return nil, 0, fmt.Errorf("unexpected end of CBOR data")
+		}
+
+		if b[pos]>>5 == 5 {
+			rs, n, err := cborMapRecords(kp, b[pos:])
+			if err != nil {
+				return nil, 0, err
+			}
+			records = append(records, rs...)
+			pos += n
+			continue
+		}
+
+		var v interface{}
+		d := codec.NewDecoderBytes(b[pos:], &cbor)
+		if err := d.Decode(&v); err != nil {
+			return nil, 0, fmt.Errorf("resource %s: %w", kp, err)
+		}
+		pos += d.NumBytesRead()
+
+		r, ok, err := cborRecord(kp, v)
+		if err != nil {
+			return nil, 0, err
+		}
+		if ok {
+			records = append(records, r)
+		}
+	}
+
+	return records, pos, nil
+}
+
+// cborRecord returns the record for a decoded resource value.
+func cborRecord(p Path, v interface{}) (senml.Record, bool, error) {
+	if p.Level() < LevelResource {
+		return senml.Record{}, false, fmt.Errorf("value at %s is not a resource", p)
+	}
+
+	switch t := v.(type) {
+	case []interface{}, map[interface{}]interface{}:
+		return senml.Record{}, false, fmt.Errorf("resource %s: unsupported value %v", p, v)
+	case time.Time:
+		v = t.Unix()
+	case uint64:
+		if resourceType(p, TypeInteger) != TypeUnsignedInteger {
+			v = int64(t)
+		}
+	case string:
+		if resourceType(p, TypeString) == TypeObjectLink {
+			l, err := ParseObjectLink(t)
+			if err != nil {
+				return senml.Record{}, false, fmt.Errorf("resource %s: %w", p, err)
+			}
+			v = l
+		}
+	}
+
+	r, ok := valueRecord(p, v)
+	return r, ok, nil
+}
+
+// cborKeyPath returns the path extended with the IDs of the map key at the start of b.
+// A key is either a single ID, or an array of IDs for multiple levels of the path.
+// It also returns the encoded length of the key.
+func cborKeyPath(p Path, b []byte) (Path, int, error) {
+	major, arg, pos, err := cborHead(b)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := 1
+	if major == 4 {
+		if arg < 0 {
+			return nil, 0, fmt.Errorf("unsupported indefinite length key below %s", p)
+		}
+		ids = arg
+	} else {
+		pos = 0
+	}
+
+	for i := 0; i < ids; i++ {
+		major, id, n, err := cborHead(b[pos:])
+		if err != nil {
+			return nil, 0, err
+		}
+		if major != 0 || id > MaxID {
+			return nil, 0, fmt.Errorf("invalid key below %s", p)
+		}
+		p = p.Child(uint16(id))
+		pos += n
+	}
+
+	if p.Level() > LevelResourceInstance {
+		return nil, 0, fmt.Errorf("path %s has too many levels", p)
+	}
+	return p, pos, nil
+}
+
+// cborHead returns the major type and argument of the CBOR item at the start of b,
+// and the length of the head. The argument is -1 for indefinite length items.
+func cborHead(b []byte) (major byte, arg int, n int, err error) {
+	if len(b) == 0 {
+		return 0, 0, 0, fmt.Errorf("unexpected end of CBOR data")
+	}
+
+	major, ai := b[0]>>5, b[0]&0x1f
+	switch {
+	case ai < 24:
+		return major, int(ai), 1, nil
+	case ai == 31:
+		return major, -1, 1, nil
+	case ai > 27:
+		return 0, 0, 0, fmt.Errorf("invalid CBOR additional information %d", ai)
+	}
+
+	l := 1 << (ai - 24)
+	if len(b) < 1+l {
+		return 0, 0, 0, fmt.Errorf("unexpected end of CBOR data")
+	}
+	var v uint64
+	for _, c := range b[1 : 1+l] {
+		v = v<<8 | uint64(c)
+	}
+	if v > math.MaxInt32 {
+		return 0, 0, 0, fmt.Errorf("CBOR argument %d too large", v)
+	}
+	return major, int(v), 1 + l, nil
+}
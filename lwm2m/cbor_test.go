@@ -0,0 +1,147 @@
+package lwm2m
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/silkeh/senml"
+	"github.com/silkeh/senml/internal/senmltest"
+)
+
+func TestEncodeCBOR(t *testing.T) {
+	list := []senml.Measurement{
+		senml.NewValue("/3303/0/5700", 23.5, senml.Celsius, time.Time{}, 0),
+		senml.NewValue("/3/0/9", 100, senml.RemainingBatteryPercent, time.Time{}, 0),
+	}
+
+	// {3: {0: {9: 100}}, 3303: {0: {5700: 23.5}}}
+	expected := []byte{
+		0xa2,
+		0x03, 0xa1, 0x00, 0xa1, 0x09, 0x18, 0x64,
+		0x19, 0x0c, 0xe7, 0xa1, 0x00, 0xa1, 0x19, 0x16, 0x44, 0xfb, 0x40, 0x37, 0x80, 0, 0, 0, 0, 0,
+	}
+
+	b, err := EncodeCBOR(list)
+	if err != nil {
+		t.Fatalf("Error encoding: %s", err)
+	}
+	if !bytes.Equal(b, expected) {
+		t.Errorf("Encoding incorrect, got:\n%x\nexpected:\n%x", b, expected)
+	}
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	senml.AutoTime = false
+	registerTestObject(t)
+	defer unregisterObject(testObjectID)
+
+	// {[3303, 0]: {5700: 23.5, 5701: "Cel"}, 3: {0: {_ 9: 100, 13: 1(1367491215), 7: {0: 3800}}}, [32800, 0, 3]: "3303:0"}
+	b := []byte{
+		0xa3,
+		0x82, 0x19, 0x0c, 0xe7, 0x00,
+		0xa2, 0x19, 0x16, 0x44, 0xf9, 0x4d, 0xe0, 0x19, 0x16, 0x45, 0x63, 'C', 'e', 'l',
+		0x03, 0xa1, 0x00, 0xbf,
+		0x09, 0x18, 0x64,
+		0x0d, 0xc1, 0x1a, 0x51, 0x82, 0x42, 0x8f,
+		0x07, 0xa1, 0x00, 0x19, 0x0e, 0xd8,
+		0xff,
+		0x83, 0x19, 0x80, 0x20, 0x00, 0x03, 0x66, '3', '3', '0', '3', ':', '0',
+	}
+	expected := []senml.Measurement{
+		senml.NewValue("/3303/0/5700", 23.5, senml.Celsius, time.Time{}, 0),
+		senml.NewString("/3303/0/5701", "Cel", senml.None, time.Time{}, 0),
+		senml.NewValue("/3/0/9", 100, senml.RemainingBatteryPercent, time.Time{}, 0),
+		senml.NewValue("/3/0/13", 1367491215, senml.None, time.Time{}, 0),
+		senml.NewValue("/3/0/7/0", 3800, senml.Millivolt, time.Time{}, 0),
+		senml.NewObjectLink("/32800/0/3", "3303:0", senml.None, time.Time{}, 0),
+	}
+
+	list, err := DecodeCBOR(b)
+	if err != nil {
+		t.Fatalf("Error decoding: %s", err)
+	}
+	if !senmltest.Equal(list, expected) {
+		t.Errorf("Decoding incorrect, got:\n%s\nexpected:\n%s", senmltest.String(list), senmltest.String(expected))
+	}
+}
+
+func TestCBORCodec(t *testing.T) {
+	senml.AutoTime = false
+	c := CBORCodec()
+	if c.MediaType() != MediaTypeCBOR {
+		t.Errorf("Media type incorrect, got %q", c.MediaType())
+	}
+	if c.ContentFormat() != ContentFormatCBOR {
+		t.Errorf("Content-Format incorrect, got %d", c.ContentFormat())
+	}
+
+	b, err := c.Encode(senml.Encode(testDevice))
+	if err != nil {
+		t.Fatalf("Error encoding: %s", err)
+	}
+	records, err := c.Decode(b)
+	if err != nil {
+		t.Fatalf("Error decoding: %s", err)
+	}
+	list, err := senml.Decode(records)
+	if err != nil {
+		t.Fatalf("Error decoding records: %s", err)
+	}
+
+	for _, exp := range testDevice {
+		found := false
+		for _, m := range list {
+			found = found || m.Equal(exp)
+		}
+		if !found {
+			t.Errorf("Missing %s in result:\n%s", senml.Format(exp, senml.English), senmltest.String(list))
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		if err := Register(); err != nil {
+			t.Fatalf("Error registering codec: %s", err)
+		}
+	}
+	if _, ok := senml.LookupMediaType(MediaTypeCBOR); !ok {
+		t.Errorf("Codec for %s not registered", MediaTypeCBOR)
+	}
+}
+
+func TestCBORErrors(t *testing.T) {
+	registerTestObject(t)
+	defer unregisterObject(testObjectID)
+
+	encode := map[string][]senml.Measurement{
+		"not resource": {senml.NewValue("/3/0", 1, senml.None, time.Time{}, 0)},
+		"invalid path": {senml.NewValue("3/0/1", 1, senml.None, time.Time{}, 0)},
+		"duplicate":    {testDevice[4], testDevice[4]},
+		"instance":     {testDevice[4], senml.NewValue("/3/0/9/0", 1, senml.None, time.Time{}, 0)},
+		"unit":         {senml.NewValue("/3/0/9", 1, senml.Celsius, time.Time{}, 0)},
+	}
+	for n, list := range encode {
+		if _, err := EncodeCBOR(list); err == nil {
+			t.Errorf("Expected error encoding %s", n)
+		}
+	}
+
+	decode := map[string][]byte{
+		"not a map":       {0x01},
+		"not a resource":  {0xa1, 0x03, 0x01},
+		"too many levels": {0xa1, 0x85, 0x01, 0x02, 0x03, 0x04, 0x05, 0x01},
+		"invalid key":     {0xa1, 0x61, 'a', 0x01},
+		"reserved ID":     {0xa1, 0x19, 0xff, 0xff, 0xa1, 0x00, 0xa1, 0x00, 0x01},
+		"array value":     {0xa1, 0x83, 0x03, 0x00, 0x09, 0x81, 0x01},
+		"truncated":       {0xa1, 0x83, 0x03, 0x00},
+		"trailing data":   {0xa0, 0x00},
+		"object link":     {0xa1, 0x83, 0x19, 0x80, 0x20, 0x00, 0x03, 0x61, 'x'},
+	}
+	for n, b := range decode {
+		if _, err := DecodeCBOR(b); err == nil {
+			t.Errorf("Expected error decoding %s", n)
+		}
+	}
+}
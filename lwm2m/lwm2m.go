@@ -0,0 +1,186 @@
+// Package lwm2m provides helpers for SenML packs used by OMA LwM2M.
+// In LwM2M, the resolved name of a record is the path of a resource,
+// eg: "/3303/0/5700" for the sensor value of the first temperature object instance.
+// Object links are encoded in the "vlo" field, see senml.ObjectLink.
+//
+// Measurements can be converted from and to the LwM2M TLV and LwM2M CBOR formats,
+// using the types and units of the resources in the object registry.
+// The LwM2M CBOR format can be registered as a SenML codec using Register.
+package lwm2m
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/silkeh/senml"
+)
+
+// Media types and CoAP Content-Formats of the LwM2M formats.
+const (
+	MediaTypeTLV  = "application/vnd.oma.lwm2m+tlv"
+	MediaTypeCBOR = "application/vnd.oma.lwm2m+cbor"
+
+	ContentFormatTLV  senml.ContentFormat = 11542
+	ContentFormatCBOR senml.ContentFormat = 11544
+)
+
+// MaxID is the maximum ID of an object, instance or resource.
+// The ID 65535 is reserved.
+const MaxID = 65534
+
+// Path levels of a LwM2M path.
+const (
+	LevelRoot = iota
+	LevelObject
+	LevelInstance
+	LevelResource
+	LevelResourceInstance
+)
+
+// Path represents a LwM2M path, consisting of an object ID, instance ID,
+// resource ID and resource instance ID. A path can be partial, eg: an object instance.
+type Path []uint16
+
+// ParsePath parses a LwM2M path from a resolved SenML name, eg: "/3303/0/5700".
+// A trailing slash is allowed, as used in base names.
+func ParsePath(name string) (Path, error) {
+	if !strings.HasPrefix(name, "/") {
+		return nil, fmt.Errorf("invalid LwM2M path %q: missing leading slash", name)
+	}
+
+	s := strings.TrimSuffix(name[1:], "/")
+	if s == "" {
+		return Path{}, nil
+	}
+
+	parts := strings.Split(s, "/")
+	if len(parts) > LevelResourceInstance {
+		return nil, fmt.Errorf("invalid LwM2M path %q: too many levels", name)
+	}
+
+	p := make(Path, len(parts))
+	for i, part := range parts {
+		id, err := strconv.ParseUint(part, 10, 16)
+		if err != nil || id > MaxID {
+			return nil, fmt.Errorf("invalid LwM2M path %q: invalid ID %q", name, part)
+		}
+		p[i] = uint16(id)
+	}
+	return p, nil
+}
+
+// MeasurementPath returns the LwM2M path of a measurement.
+func MeasurementPath(m senml.Measurement) (Path, error) {
+	return ParsePath(m.Attrs().Name)
+}
+
+// String returns the path as a SenML name, eg: "/3303/0/5700".
+func (p Path) String() string {
+	if len(p) == 0 {
+		return "/"
+	}
+
+	var b strings.Builder
+	for _, id := range p {
+		b.WriteByte('/')
+		b.WriteString(strconv.Itoa(int(id)))
+	}
+	return b.String()
+}
+
+// Level returns the level of the path, eg: LevelResource.
+func (p Path) Level() int {
+	return len(p)
+}
+
+// id returns the ID at the given level.
+func (p Path) id(level int) (uint16, bool) {
+	if len(p) < level {
+		return 0, false
+	}
+	return p[level-1], true
+}
+
+// Object returns the object ID of the path.
+func (p Path) Object() (uint16, bool) {
+	return p.id(LevelObject)
+}
+
+// Instance returns the object instance ID of the path.
+func (p Path) Instance() (uint16, bool) {
+	return p.id(LevelInstance)
+}
+
+// Resource returns the resource ID of the path.
+func (p Path) Resource() (uint16, bool) {
+	return p.id(LevelResource)
+}
+
+// ResourceInstance returns the resource instance ID of the path.
+func (p Path) ResourceInstance() (uint16, bool) {
+	return p.id(LevelResourceInstance)
+}
+
+// Parent returns the path of the parent level.
+func (p Path) Parent() Path {
+	if len(p) == 0 {
+		return p
+	}
+	return p[: len(p)-1 : len(p)-1]
+}
+
+// Child returns the path extended with the given ID.
+func (p Path) Child(id uint16) Path {
+	c := make(Path, len(p)+1)
+	copy(c, p)
+	c[len(p)] = id
+	return c
+}
+
+// Contains returns true if the given path is equal to, or below this path.
+func (p Path) Contains(c Path) bool {
+	if len(c) < len(p) {
+		return false
+	}
+	for i := range p {
+		if p[i] != c[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ObjectLink represents a LwM2M object link, referring to an object instance.
+type ObjectLink struct {
+	Object, Instance uint16
+}
+
+// ParseObjectLink parses an object link value, eg: "3303:0".
+func ParseObjectLink(s string) (ObjectLink, error) {
+	i := strings.IndexByte(s, ':')
+	if i < 0 {
+		return ObjectLink{}, fmt.Errorf("invalid object link %q: missing separator", s)
+	}
+
+	obj, err := strconv.ParseUint(s[:i], 10, 16)
+	if err != nil {
+		return ObjectLink{}, fmt.Errorf("invalid object link %q: invalid object ID", s)
+	}
+	inst, err := strconv.ParseUint(s[i+1:], 10, 16)
+	if err != nil {
+		return ObjectLink{}, fmt.Errorf("invalid object link %q: invalid instance ID", s)
+	}
+
+	return ObjectLink{Object: uint16(obj), Instance: uint16(inst)}, nil
+}
+
+// String returns the object link as a SenML value, eg: "3303:0".
+func (l ObjectLink) String() string {
+	return strconv.Itoa(int(l.Object)) + ":" + strconv.Itoa(int(l.Instance))
+}
+
+// Path returns the path of the linked object instance.
+func (l ObjectLink) Path() Path {
+	return Path{l.Object, l.Instance}
+}
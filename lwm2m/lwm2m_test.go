@@ -0,0 +1,123 @@
+package lwm2m
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/silkeh/senml"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := map[string]Path{
+		"/":             {},
+		"/3303":         {3303},
+		"/3303/0/":      {3303, 0},
+		"/3303/0/5700":  {3303, 0, 5700},
+		"/3/0/11/2":     {3, 0, 11, 2},
+		"/65534/0/0/99": {MaxID, 0, 0, 99},
+	}
+
+	for name, exp := range tests {
+		p, err := ParsePath(name)
+		if err != nil {
+			t.Errorf("Error parsing %q: %s", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(p, exp) {
+			t.Errorf("Path for %q incorrect, got:\n%v\nexpected:\n%v", name, []uint16(p), []uint16(exp))
+		}
+		if s := p.String(); s != "/" && s+"/" != name && s != name {
+			t.Errorf("String of %q incorrect, got: %s", name, s)
+		}
+	}
+
+	for _, name := range []string{"", "3303/0", "/3303//5700", "/a", "/65535", "/-1", "/1/2/3/4/5"} {
+		if _, err := ParsePath(name); err == nil {
+			t.Errorf("Expected error parsing %q", name)
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	p := Path{3303, 0, 5700}
+
+	if id, ok := p.Object(); !ok || id != 3303 {
+		t.Errorf("Object incorrect, got %d, %v", id, ok)
+	}
+	if id, ok := p.Instance(); !ok || id != 0 {
+		t.Errorf("Instance incorrect, got %d, %v", id, ok)
+	}
+	if id, ok := p.Resource(); !ok || id != 5700 {
+		t.Errorf("Resource incorrect, got %d, %v", id, ok)
+	}
+	if _, ok := p.ResourceInstance(); ok {
+		t.Errorf("Expected no resource instance in %s", p)
+	}
+	if p.Level() != LevelResource {
+		t.Errorf("Level incorrect, got %d", p.Level())
+	}
+
+	parent := p.Parent()
+	if parent.String() != "/3303/0" || !parent.Contains(p) || p.Contains(parent) {
+		t.Errorf("Parent incorrect, got %s", parent)
+	}
+
+	child := parent.Child(5701)
+	if child.String() != "/3303/0/5701" || p.String() != "/3303/0/5700" {
+		t.Errorf("Child incorrect, got %s, modified original: %s", child, p)
+	}
+
+	if root := (Path{}); root.Parent().String() != "/" || !root.Contains(p) {
+		t.Errorf("Root path incorrect")
+	}
+}
+
+func TestMeasurementPath(t *testing.T) {
+	list, err := senml.DecodeJSON([]byte(`[{"bn":"/3303/0/","n":"5700","v":23.5},{"n":"5701","vs":"Cel"}]`))
+	if err != nil {
+		t.Fatalf("Error decoding: %s", err)
+	}
+
+	for i, exp := range []string{"/3303/0/5700", "/3303/0/5701"} {
+		p, err := MeasurementPath(list[i])
+		if err != nil {
+			t.Errorf("Error parsing path of %s: %s", list[i].Attrs().Name, err)
+		} else if p.String() != exp {
+			t.Errorf("Path incorrect, got %s, expected %s", p, exp)
+		}
+	}
+}
+
+func TestObjectLink(t *testing.T) {
+	list, err := senml.DecodeJSON([]byte(`[{"n":"/65/0/0/0","vlo":"3303:0"}]`))
+	if err != nil {
+		t.Fatalf("Error decoding: %s", err)
+	}
+
+	m, ok := list[0].(*senml.ObjectLink)
+	if !ok {
+		t.Fatalf("Incorrect measurement type %T", list[0])
+	}
+
+	l, err := ParseObjectLink(m.Value)
+	if err != nil {
+		t.Fatalf("Error parsing object link: %s", err)
+	}
+	if exp := (ObjectLink{3303, 0}); l != exp {
+		t.Errorf("Object link incorrect, got %v, expected %v", l, exp)
+	}
+	if l.String() != m.Value || l.Path().String() != "/3303/0" {
+		t.Errorf("Object link string incorrect, got %s, path %s", l, l.Path())
+	}
+
+	exp := senml.NewObjectLink("/65/0/0/0", l.String(), senml.None, m.Time, 0)
+	if !exp.Equal(m) {
+		t.Errorf("Measurement incorrect, got %#v", m)
+	}
+
+	for _, s := range []string{"", "3303", "3303:", ":0", "a:0", "3303:65536"} {
+		if _, err := ParseObjectLink(s); err == nil {
+			t.Errorf("Expected error parsing %q", s)
+		}
+	}
+}
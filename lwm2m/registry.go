@@ -0,0 +1,225 @@
+package lwm2m
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/silkeh/senml"
+)
+
+// ResourceType represents the data type of a LwM2M resource.
+type ResourceType int
+
+// Resource types defined by LwM2M.
+const (
+	TypeNone ResourceType = iota
+	TypeString
+	TypeInteger
+	TypeUnsignedInteger
+	TypeFloat
+	TypeBoolean
+	TypeOpaque
+	TypeTime
+	TypeObjectLink
+	TypeCoreLink
+)
+
+// resourceTypeNames contains the names of the resource types.
+var resourceTypeNames = map[ResourceType]string{
+	TypeNone:            "None",
+	TypeString:          "String",
+	TypeInteger:         "Integer",
+	TypeUnsignedInteger: "Unsigned Integer",
+	TypeFloat:           "Float",
+	TypeBoolean:         "Boolean",
+	TypeOpaque:          "Opaque",
+	TypeTime:            "Time",
+	TypeObjectLink:      "Objlnk",
+	TypeCoreLink:        "Corelnk",
+}
+
+// String returns the name of the resource type, eg: "Float".
+func (t ResourceType) String() string {
+	if n, ok := resourceTypeNames[t]; ok {
+		return n
+	}
+	return fmt.Sprintf("ResourceType(%d)", int(t))
+}
+
+// Resource contains the definition of a LwM2M resource.
+type Resource struct {
+	// ID is the ID of the resource, eg: 5700.
+	ID uint16
+
+	// Name is the name of the resource, eg: "Sensor Value".
+	Name string
+
+	// Type is the data type of the resource.
+	Type ResourceType
+
+	// Unit is the SenML unit of the resource, if any.
+	Unit senml.Unit
+
+	// Multiple is true for resources with multiple instances.
+	Multiple bool
+}
+
+// Object contains the definition of a LwM2M object.
+type Object struct {
+	// ID is the ID of the object, eg: 3303.
+	ID uint16
+
+	// Name is the name of the object, eg: "Temperature".
+	Name string
+
+	// Multiple is true for objects with multiple instances.
+	Multiple bool
+
+	// Resources contains the definitions of the resources of the object.
+	Resources []Resource
+}
+
+// Resource returns the definition of a resource of the object.
+func (o Object) Resource(id uint16) (Resource, bool) {
+	for _, r := range o.Resources {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return Resource{}, false
+}
+
+// objectRegistry contains the definitions of all known objects.
+var (
+	objectRegistry      = make(map[uint16]Object)
+	objectRegistryMutex sync.RWMutex
+)
+
+// ipsoSensor returns the definition of an IPSO sensor object measuring in the given unit.
+func ipsoSensor(id uint16, name string, unit senml.Unit) Object {
+	return Object{ID: id, Name: name, Multiple: true, Resources: []Resource{
+		{ID: 5518, Name: "Timestamp", Type: TypeTime},
+		{ID: 5601, Name: "Min Measured Value", Type: TypeFloat, Unit: unit},
+		{ID: 5602, Name: "Max Measured Value", Type: TypeFloat, Unit: unit},
+		{ID: 5603, Name: "Min Range Value", Type: TypeFloat, Unit: unit},
+		{ID: 5604, Name: "Max Range Value", Type: TypeFloat, Unit: unit},
+		{ID: 5605, Name: "Reset Min and Max Measured Values", Type: TypeNone},
+		{ID: 5700, Name: "Sensor Value", Type: TypeFloat, Unit: unit},
+		{ID: 5701, Name: "Sensor Units", Type: TypeString},
+		{ID: 5750, Name: "Application Type", Type: TypeString},
+		{ID: 6050, Name: "Fractional Timestamp", Type: TypeFloat, Unit: senml.Second},
+	}}
+}
+
+// init fills the object registry with the LwM2M device object and common IPSO sensor objects.
+func init() {
+	objects := []Object{
+		{ID: 3, Name: "Device", Resources: []Resource{
+			{ID: 0, Name: "Manufacturer", Type: TypeString},
+			{ID: 1, Name: "Model Number", Type: TypeString},
+			{ID: 2, Name: "Serial Number", Type: TypeString},
+			{ID: 3, Name: "Firmware Version", Type: TypeString},
+			{ID: 4, Name: "Reboot", Type: TypeNone},
+			{ID: 5, Name: "Factory Reset", Type: TypeNone},
+			{ID: 6, Name: "Available Power Sources", Type: TypeInteger, Multiple: true},
+			{ID: 7, Name: "Power Source Voltage", Type: TypeInteger, Unit: senml.Millivolt, Multiple: true},
+			{ID: 8, Name: "Power Source Current", Type: TypeInteger, Unit: senml.Milliampere, Multiple: true},
+			{ID: 9, Name: "Battery Level", Type: TypeInteger, Unit: senml.RemainingBatteryPercent},
+			{ID: 10, Name: "Memory Free", Type: TypeInteger, Unit: senml.Kibibyte},
+			{ID: 11, Name: "Error Code", Type: TypeInteger, Multiple: true},
+			{ID: 12, Name: "Reset Error Code", Type: TypeNone},
+			{ID: 13, Name: "Current Time", Type: TypeTime},
+			{ID: 14, Name: "UTC Offset", Type: TypeString},
+			{ID: 15, Name: "Timezone", Type: TypeString},
+			{ID: 16, Name: "Supported Binding and Modes", Type: TypeString},
+			{ID: 17, Name: "Device Type", Type: TypeString},
+			{ID: 18, Name: "Hardware Version", Type: TypeString},
+			{ID: 19, Name: "Software Version", Type: TypeString},
+			{ID: 20, Name: "Battery Status", Type: TypeInteger},
+			{ID: 21, Name: "Memory Total", Type: TypeInteger, Unit: senml.Kibibyte},
+		}},
+		ipsoSensor(3300, "Generic Sensor", senml.None),
+		ipsoSensor(3301, "Illuminance", senml.Lux),
+		ipsoSensor(3303, "Temperature", senml.Celsius),
+		ipsoSensor(3304, "Humidity", senml.RelativeHumidityPercent),
+		ipsoSensor(3315, "Barometer", senml.Pascal),
+		ipsoSensor(3316, "Voltage", senml.Volt),
+		ipsoSensor(3317, "Current", senml.Ampere),
+		ipsoSensor(3323, "Pressure", senml.Pascal),
+		ipsoSensor(3328, "Power", senml.Watt),
+		ipsoSensor(3330, "Distance", senml.Meter),
+	}
+
+	for _, o := range objects {
+		if err := RegisterObject(o); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterObject registers the definition of an object.
+// An error is returned if the object is already registered,
+// or if it contains duplicate resource IDs.
+func RegisterObject(o Object) error {
+	if o.ID > MaxID {
+		return fmt.Errorf("invalid object ID %d", o.ID)
+	}
+
+	ids := make(map[uint16]bool, len(o.Resources))
+	for _, r := range o.Resources {
+		if r.ID > MaxID {
+			return fmt.Errorf("object %d: invalid resource ID %d", o.ID, r.ID)
+		}
+		if ids[r.ID] {
+			return fmt.Errorf("object %d: duplicate resource ID %d", o.ID, r.ID)
+		}
+		ids[r.ID] = true
+	}
+
+	objectRegistryMutex.Lock()
+	defer objectRegistryMutex.Unlock()
+
+	if _, ok := objectRegistry[o.ID]; ok {
+		return fmt.Errorf("object %d is already registered", o.ID)
+	}
+
+	objectRegistry[o.ID] = o
+	return nil
+}
+
+// LookupObject returns the definition of the object with the given ID.
+func LookupObject(id uint16) (Object, bool) {
+	objectRegistryMutex.RLock()
+	defer objectRegistryMutex.RUnlock()
+
+	o, ok := objectRegistry[id]
+	return o, ok
+}
+
+// LookupResource returns the definition of the resource referred to by a path.
+// The path must contain at least an object, instance and resource ID.
+func LookupResource(p Path) (Resource, bool) {
+	if p.Level() < LevelResource {
+		return Resource{}, false
+	}
+
+	o, ok := LookupObject(p[0])
+	if !ok {
+		return Resource{}, false
+	}
+	return o.Resource(p[2])
+}
+
+// Objects returns the IDs of all registered objects, sorted by ID.
+func Objects() []uint16 {
+	objectRegistryMutex.RLock()
+	ids := make([]uint16, 0, len(objectRegistry))
+	for id := range objectRegistry {
+		ids = append(ids, id)
+	}
+	objectRegistryMutex.RUnlock()
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,87 @@
+package lwm2m
+
+import (
+	"testing"
+
+	"github.com/silkeh/senml"
+)
+
+func TestLookupResource(t *testing.T) {
+	tests := map[string]Resource{
+		"/3303/0/5700":  {ID: 5700, Name: "Sensor Value", Type: TypeFloat, Unit: senml.Celsius},
+		"/3304/1/5601":  {ID: 5601, Name: "Min Measured Value", Type: TypeFloat, Unit: senml.RelativeHumidityPercent},
+		"/3/0/9":        {ID: 9, Name: "Battery Level", Type: TypeInteger, Unit: senml.RemainingBatteryPercent},
+		"/3/0/11/0":     {ID: 11, Name: "Error Code", Type: TypeInteger, Multiple: true},
+		"/3300/0/5750/": {ID: 5750, Name: "Application Type", Type: TypeString},
+	}
+
+	for name, exp := range tests {
+		p, err := ParsePath(name)
+		if err != nil {
+			t.Fatalf("Error parsing %q: %s", name, err)
+		}
+
+		r, ok := LookupResource(p)
+		if !ok {
+			t.Errorf("Resource %s not found", name)
+			continue
+		}
+		if r != exp {
+			t.Errorf("Resource %s incorrect, got:\n%#v\nexpected:\n%#v", name, r, exp)
+		}
+	}
+
+	for _, p := range []Path{{3303, 0}, {3303, 0, 1}, {1234, 0, 5700}} {
+		if r, ok := LookupResource(p); ok {
+			t.Errorf("Expected no resource for %s, got %#v", p, r)
+		}
+	}
+}
+
+// unregisterObject removes an object registered in a test from the registry.
+func unregisterObject(id uint16) {
+	objectRegistryMutex.Lock()
+	defer objectRegistryMutex.Unlock()
+	delete(objectRegistry, id)
+}
+
+func TestRegisterObject(t *testing.T) {
+	o := Object{ID: 32769, Name: "Test", Resources: []Resource{
+		{ID: 0, Name: "Level", Type: TypeUnsignedInteger, Unit: senml.Percent},
+	}}
+	if err := RegisterObject(o); err != nil {
+		t.Fatalf("Error registering object: %s", err)
+	}
+	defer unregisterObject(o.ID)
+
+	if r, ok := LookupResource(Path{32769, 0, 0}); !ok || r.Unit != senml.Percent {
+		t.Errorf("Registered resource incorrect, got %#v", r)
+	}
+
+	found := false
+	for _, id := range Objects() {
+		found = found || id == o.ID
+	}
+	if !found {
+		t.Errorf("Registered object not in %v", Objects())
+	}
+
+	for n, o := range map[string]Object{
+		"duplicate object":   {ID: 3303},
+		"duplicate resource": {ID: 32770, Resources: []Resource{{ID: 1}, {ID: 1}}},
+		"reserved object ID": {ID: 65535},
+		"reserved resource":  {ID: 32771, Resources: []Resource{{ID: 65535}}},
+	} {
+		if err := RegisterObject(o); err == nil {
+			t.Errorf("Expected error registering %s", n)
+		}
+	}
+}
+
+func TestResourceType(t *testing.T) {
+	for typ, exp := range map[ResourceType]string{TypeFloat: "Float", TypeObjectLink: "Objlnk", 99: "ResourceType(99)"} {
+		if s := typ.String(); s != exp {
+			t.Errorf("String of type %d incorrect, got %q, expected %q", int(typ), s, exp)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package lwm2m
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/silkeh/senml"
+)
+
+// TLV identifier types.
+const (
+	tlvObjectInstance   = 0
+	tlvResourceInstance = 1
+	tlvMultipleResource = 2
+	tlvResource         = 3
+)
+
+// tlvLevels contains the path level of each TLV identifier type.
+var tlvLevels = [...]int{
+	tlvObjectInstance:   LevelInstance,
+	tlvResourceInstance: LevelResourceInstance,
+	tlvMultipleResource: LevelResource,
+	tlvResource:         LevelResource,
+}
+
+// tlv represents a single TLV entry.
+type tlv struct {
+	Type  byte
+	ID    uint16
+	Value []byte
+}
+
+// EncodeTLV encodes a list of measurements into LwM2M TLV.
+// The names of the measurements must be paths of resources or resource
+// instances below the given base path, which is the path of the request.
+// The base path must at least refer to an object.
+// Numeric values are encoded using the type and unit of registered resources,
+// or as a float otherwise.
+func EncodeTLV(base Path, list []senml.Measurement) ([]byte, error) {
+	if base.Level() < LevelObject {
+		return nil, fmt.Errorf("TLV requires an object path, got %s", base)
+	}
+
+	root := new(tlvNode)
+	for _, m := range list {
+		p, err := MeasurementPath(m)
+		if err != nil {
+			return nil, err
+		}
+		if p.Level() < LevelResource || !base.Contains(p) {
+			return nil, fmt.Errorf("path %s is not a resource below %s", p, base)
+		}
+
+		v, err := measurementValue(p, m)
+		if err != nil {
+			return nil, err
+		}
+		b, err := tlvEncodeValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("resource %s: %w", p, err)
+		}
+		if err := root.insert(p, b); err != nil {
+			return nil, fmt.Errorf("resource %s: %w", p, err)
+		}
+	}
+
+	n := root.find(base)
+	if n == nil {
+		return []byte{}, nil
+	}
+	if base.Level() < LevelResource {
+		return n.encodeChildren(base.Level() + 1)
+	}
+	return n.encode(base.Level())
+}
+
+// tlvNode is a node in the tree of resources to encode.
+type tlvNode struct {
+	ID       uint16
+	Value    []byte
+	Children []*tlvNode
+}
+
+// insert inserts a value at the given path.
+func (n *tlvNode) insert(p Path, value []byte) error {
+	if len(p) == 0 {
+		if n.Value != nil || len(n.Children) > 0 {
+			return fmt.Errorf("duplicate value")
+		}
+		n.Value = append([]byte{}, value...)
+		return nil
+	}
+	if n.Value != nil {
+		return fmt.Errorf("resource instance of a single instance resource")
+	}
+
+	var c *tlvNode
+	for _, child := range n.Children {
+		if child.ID == p[0] {
+			c = child
+		}
+	}
+	if c == nil {
+		c = &tlvNode{ID: p[0]}
+		n.Children = append(n.Children, c)
+	}
+
+	return c.insert(p[1:], value)
+}
+
+// find returns the node at the given path.
+func (n *tlvNode) find(p Path) *tlvNode {
+	for _, id := range p {
+		var next *tlvNode
+		for _, c := range n.Children {
+			if c.ID == id {
+				next = c
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		n = next
+	}
+	return n
+}
+
+// encode encodes the node as an entry of the given path level.
+func (n *tlvNode) encode(level int) ([]byte, error) {
+	switch {
+	case level == LevelInstance:
+		b, err := n.encodeChildren(LevelResource)
+		return tlvAppend(nil, tlvObjectInstance, n.ID, b), err
+	case level == LevelResource && n.Value == nil:
+		b, err := n.encodeChildren(LevelResourceInstance)
+		return tlvAppend(nil, tlvMultipleResource, n.ID, b), err
+	case level == LevelResource:
+		return tlvAppend(nil, tlvResource, n.ID, n.Value), nil
+	case level == LevelResourceInstance:
+		return tlvAppend(nil, tlvResourceInstance, n.ID, n.Value), nil
+	default:
+		return nil, fmt.Errorf("cannot encode path level %d in TLV", level)
+	}
+}
+
+// encodeChildren encodes the children of the node as entries of the given path level.
+func (n *tlvNode) encodeChildren(level int) ([]byte, error) {
+	var b []byte
+	for _, c := range n.Children {
+		cb, err := c.encode(level)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, cb...)
+	}
+	return b, nil
+}
+
+// tlvAppend appends a TLV entry to a buffer.
+func tlvAppend(b []byte, typ byte, id uint16, value []byte) []byte {
+	header := typ << 6
+	var ib []byte
+	if id > math.MaxUint8 {
+		header |= 0x20
+		ib = []byte{byte(id >> 8), byte(id)}
+	} else {
+		ib = []byte{byte(id)}
+	}
+
+	n := len(value)
+	var lb []byte
+	switch {
+	case n < 8:
+		header |= byte(n)
+	case n <= math.MaxUint8:
+		header |= 0x08
+		lb = []byte{byte(n)}
+	case n <= math.MaxUint16:
+		header |= 0x10
+		lb = []byte{byte(n >> 8), byte(n)}
+	default:
+		header |= 0x18
+		lb = []byte{byte(n >> 16), byte(n >> 8), byte(n)}
+	}
+
+	b = append(b, header)
+	b = append(b, ib...)
+	b = append(b, lb...)
+	return append(b, value...)
+}
+
+// tlvUint returns the big-endian encoding of a value using n bytes.
+func tlvUint(v uint64, n int) []byte {
+	b := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+	return b
+}
+
+// tlvEncodeValue encodes a resource value.
+func tlvEncodeValue(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case int64:
+		switch {
+		case v >= math.MinInt8 && v <= math.MaxInt8:
+			return []byte{byte(v)}, nil
+		case v >= math.MinInt16 && v <= math.MaxInt16:
+			return tlvUint(uint64(v), 2), nil
+		case v >= math.MinInt32 && v <= math.MaxInt32:
+			return tlvUint(uint64(v), 4), nil
+		default:
+			return tlvUint(uint64(v), 8), nil
+		}
+	case uint64:
+		switch {
+		case v <= math.MaxUint8:
+			return []byte{byte(v)}, nil
+		case v <= math.MaxUint16:
+			return tlvUint(uint64(v), 2), nil
+		case v <= math.MaxUint32:
+			return tlvUint(uint64(v), 4), nil
+		default:
+			return tlvUint(uint64(v), 8), nil
+		}
+	case float64:
+		return tlvUint(math.Float64bits(v), 8), nil
+	case bool:
+		if v {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case ObjectLink:
+		return []byte{byte(v.Object >> 8), byte(v.Object), byte(v.Instance >> 8), byte(v.Instance)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+// DecodeTLV decodes a list of measurements from LwM2M TLV.
+// The given base path is the path of the request the TLV was sent for,
+// and is used to resolve the paths of the entries.
+// Values are decoded using the type and unit of registered resources,
+// and unknown resources are decoded as Data.
+// Resources with empty values are skipped, as these cannot be represented in SenML.
+func DecodeTLV(base Path, b []byte) ([]senml.Measurement, error) {
+	records, err := tlvRecords(base, b, true)
+	if err != nil {
+		return nil, err
+	}
+	return senml.Decode(records)
+}
+
+// tlvRecords returns the records for the TLV entries below a path.
+// If top is true, the path may refer to the entries themselves.
+func tlvRecords(p Path, b []byte, top bool) ([]senml.Record, error) {
+	var records []senml.Record
+	for len(b) > 0 {
+		e, n, err := tlvRead(b)
+		if err != nil {
+			return nil, err
+		}
+		b = b[n:]
+
+		level := tlvLevels[e.Type]
+		parent := p
+		switch {
+		case top && p.Level() >= level:
+			if p[level-1] != e.ID {
+				return nil, fmt.Errorf("TLV entry %d does not match path %s", e.ID, p)
+			}
+			parent = p[:level-1]
+		case p.Level() != level-1:
+			return nil, fmt.Errorf("unexpected TLV entry type %d below %s", e.Type, p)
+		}
+		ep := parent.Child(e.ID)
+
+		switch e.Type {
+		case tlvObjectInstance, tlvMultipleResource:
+			rs, err := tlvRecords(ep, e.Value, false)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, rs...)
+		case tlvResource, tlvResourceInstance:
+			v, err := tlvDecodeValue(resourceType(ep, TypeOpaque), e.Value)
+			if err != nil {
+				return nil, fmt.Errorf("resource %s: %w", ep, err)
+			}
+			if r, ok := valueRecord(ep, v); ok {
+				records = append(records, r)
+			}
+		}
+	}
+	return records, nil
+}
+
+// tlvRead reads a TLV entry, and returns it together with its encoded length.
+func tlvRead(b []byte) (e tlv, n int, err error) {
+	header := b[0]
+	e.Type = header >> 6
+	n = 1
+
+	idLen := 1 + int(header>>5&1)
+	lenLen := int(header >> 3 & 3)
+	if len(b) < n+idLen+lenLen {
+		return e, 0, fmt.Errorf("unexpected end of TLV data")
+	}
+
+	for _, c := range b[n : n+idLen] {
+		e.ID = e.ID<<8 | uint16(c)
+	}
+	n += idLen
+
+	length := int(header & 7)
+	if lenLen > 0 {
+		length = 0
+		for _, c := range b[n : n+lenLen] {
+			length = length<<8 | int(c)
+		}
+		n += lenLen
+	}
+
+	if len(b) < n+length {
+		return e, 0, fmt.Errorf("unexpected end of TLV data")
+	}
+	e.Value = b[n : n+length]
+	return e, n + length, nil
+}
+
+// tlvDecodeValue decodes a resource value of the given type.
+func tlvDecodeValue(t ResourceType, b []byte) (interface{}, error) {
+	switch t {
+	case TypeInteger, TypeTime:
+		switch len(b) {
+		case 1:
+			return int64(int8(b[0])), nil
+		case 2:
+			return int64(int16(binary.BigEndian.Uint16(b))), nil
+		case 4:
+			return int64(int32(binary.BigEndian.Uint32(b))), nil
+		case 8:
+			return int64(binary.BigEndian.Uint64(b)), nil
+		}
+	case TypeUnsignedInteger:
+		switch len(b) {
+		case 1:
+			return uint64(b[0]), nil
+		case 2:
+			return uint64(binary.BigEndian.Uint16(b)), nil
+		case 4:
+			return uint64(binary.BigEndian.Uint32(b)), nil
+		case 8:
+			return binary.BigEndian.Uint64(b), nil
+		}
+	case TypeFloat:
+		switch len(b) {
+		case 4:
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+		case 8:
+			return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+		}
+	case TypeBoolean:
+		if len(b) == 1 && b[0] <= 1 {
+			return b[0] == 1, nil
+		}
+	case TypeObjectLink:
+		if len(b) == 4 {
+			return ObjectLink{
+				Object:   binary.BigEndian.Uint16(b),
+				Instance: binary.BigEndian.Uint16(b[2:]),
+			}, nil
+		}
+	case TypeString, TypeCoreLink:
+		return string(b), nil
+	case TypeNone:
+		return nil, nil
+	default:
+		return b, nil
+	}
+	return nil, fmt.Errorf("invalid length %d of %s value", len(b), t)
+}
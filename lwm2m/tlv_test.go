@@ -0,0 +1,169 @@
+package lwm2m
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/silkeh/senml"
+	"github.com/silkeh/senml/internal/senmltest"
+)
+
+// testDeviceTLV contains resources of the LwM2M device object example.
+var testDeviceTLV = []byte{
+	0xc8, 0x00, 0x14, 'O', 'p', 'e', 'n', ' ', 'M', 'o', 'b', 'i', 'l', 'e', ' ', 'A', 'l', 'l', 'i', 'a', 'n', 'c', 'e',
+	0xc3, 0x03, '1', '.', '0',
+	0x88, 0x07, 0x08, 0x42, 0x00, 0x0e, 0xd8, 0x42, 0x01, 0x13, 0x88,
+	0xc1, 0x09, 0x64,
+	0x83, 0x0b, 0x41, 0x00, 0x00,
+	0xc4, 0x0d, 0x51, 0x82, 0x42, 0x8f,
+}
+
+var testDevice = []senml.Measurement{
+	senml.NewString("/3/0/0", "Open Mobile Alliance", senml.None, time.Time{}, 0),
+	senml.NewString("/3/0/3", "1.0", senml.None, time.Time{}, 0),
+	senml.NewValue("/3/0/7/0", 3800, senml.Millivolt, time.Time{}, 0),
+	senml.NewValue("/3/0/7/1", 5000, senml.Millivolt, time.Time{}, 0),
+	senml.NewValue("/3/0/9", 100, senml.RemainingBatteryPercent, time.Time{}, 0),
+	senml.NewValue("/3/0/11/0", 0, senml.None, time.Time{}, 0),
+	senml.NewValue("/3/0/13", 1367491215, senml.None, time.Time{}, 0),
+}
+
+func TestTLV(t *testing.T) {
+	senml.AutoTime = false
+	tests := map[string]struct {
+		Base     Path
+		TLV      []byte
+		Expected []senml.Measurement
+	}{
+		"Instance":          {Path{3, 0}, testDeviceTLV, testDevice},
+		"Object":            {Path{3}, append([]byte{0x08, 0x00, byte(len(testDeviceTLV))}, testDeviceTLV...), testDevice},
+		"Resource":          {Path{3, 0, 9}, testDeviceTLV[39:42], testDevice[4:5]},
+		"Multiple resource": {Path{3, 0, 7}, testDeviceTLV[28:39], testDevice[2:4]},
+		"Resource instance": {Path{3, 0, 7, 1}, testDeviceTLV[35:39], testDevice[3:4]},
+	}
+
+	for n, test := range tests {
+		list, err := DecodeTLV(test.Base, test.TLV)
+		if err != nil {
+			t.Errorf("Error decoding %s: %s", n, err)
+		} else if !senmltest.Equal(list, test.Expected) {
+			t.Errorf("Decoding %s incorrect, got:\n%s\nexpected:\n%s", n, senmltest.String(list), senmltest.String(test.Expected))
+		}
+
+		b, err := EncodeTLV(test.Base, test.Expected)
+		if err != nil {
+			t.Errorf("Error encoding %s: %s", n, err)
+		} else if !bytes.Equal(b, test.TLV) {
+			t.Errorf("Encoding %s incorrect, got:\n%x\nexpected:\n%x", n, b, test.TLV)
+		}
+	}
+}
+
+// testObjectID is the ID of the object registered by registerTestObject.
+const testObjectID = 32800
+
+// registerTestObject registers an object with resources of various types.
+// It should be removed using unregisterObject.
+func registerTestObject(t *testing.T) {
+	err := RegisterObject(Object{ID: testObjectID, Name: "Test", Resources: []Resource{
+		{ID: 1, Type: TypeBoolean},
+		{ID: 2, Type: TypeOpaque},
+		{ID: 3, Type: TypeObjectLink},
+		{ID: 300, Type: TypeFloat},
+	}})
+	if err != nil {
+		t.Fatalf("Error registering test object: %s", err)
+	}
+}
+
+func TestTLVValues(t *testing.T) {
+	senml.AutoTime = false
+	registerTestObject(t)
+	defer unregisterObject(testObjectID)
+	celsius, _ := senml.Kelvin.Convert(300, senml.Celsius)
+	list := []senml.Measurement{
+		senml.NewValue("/3303/0/5700", 23.5, senml.Celsius, time.Time{}, 0),
+		senml.NewValue("/3303/0/5601", 300, senml.Kelvin, time.Time{}, 0),
+		senml.NewValue("/3/0/9", -1, senml.None, time.Time{}, 0),
+		senml.NewValue("/3/0/21", 70000, senml.Kibibyte, time.Time{}, 0),
+		senml.NewBoolean("/32800/0/1", true, senml.None, time.Time{}, 0),
+		senml.NewData("/32800/0/2", bytes.Repeat([]byte{0xaa}, 300), senml.None, time.Time{}, 0),
+		senml.NewObjectLink("/32800/0/3", "3303:0", senml.None, time.Time{}, 0),
+		senml.NewValue("/32800/0/300", 1.5, senml.None, time.Time{}, 0),
+	}
+	expected := []senml.Measurement{
+		list[0],
+		senml.NewValue("/3303/0/5601", celsius, senml.Celsius, time.Time{}, 0),
+		senml.NewValue("/3/0/9", -1, senml.RemainingBatteryPercent, time.Time{}, 0),
+		list[3], list[4], list[5], list[6], list[7],
+	}
+
+	for _, base := range []Path{{3303}, {3}, {32800, 0}} {
+		var sub, exp []senml.Measurement
+		for i, m := range list {
+			if p, _ := MeasurementPath(m); base.Contains(p) {
+				sub = append(sub, m)
+				exp = append(exp, expected[i])
+			}
+		}
+
+		b, err := EncodeTLV(base, sub)
+		if err != nil {
+			t.Errorf("Error encoding %s: %s", base, err)
+			continue
+		}
+		res, err := DecodeTLV(base, b)
+		if err != nil {
+			t.Errorf("Error decoding %s: %s", base, err)
+			continue
+		}
+		if !senmltest.Equal(res, exp) {
+			t.Errorf("Result for %s incorrect, got:\n%s\nexpected:\n%s", base, senmltest.String(res), senmltest.String(exp))
+		}
+	}
+}
+
+func TestTLVErrors(t *testing.T) {
+	registerTestObject(t)
+	defer unregisterObject(testObjectID)
+	encode := map[string]struct {
+		Base Path
+		List []senml.Measurement
+	}{
+		"root":          {Path{}, testDevice},
+		"outside base":  {Path{3303}, testDevice},
+		"not resource":  {Path{3}, []senml.Measurement{senml.NewValue("/3/0", 1, senml.None, time.Time{}, 0)}},
+		"invalid path":  {Path{3}, []senml.Measurement{senml.NewValue("3/0/1", 1, senml.None, time.Time{}, 0)}},
+		"duplicate":     {Path{3}, append(testDevice[4:5:5], testDevice[4])},
+		"instance":      {Path{3}, append(testDevice[4:5:5], senml.NewValue("/3/0/9/0", 1, senml.None, time.Time{}, 0))},
+		"unit":          {Path{3}, []senml.Measurement{senml.NewValue("/3/0/9", 1, senml.Celsius, time.Time{}, 0)}},
+		"object link":   {Path{3}, []senml.Measurement{senml.NewObjectLink("/3/0/9", "x", senml.None, time.Time{}, 0)}},
+		"integer range": {Path{3}, []senml.Measurement{senml.NewValue("/3/0/9", 1e30, senml.None, time.Time{}, 0)}},
+	}
+	for n, test := range encode {
+		if _, err := EncodeTLV(test.Base, test.List); err == nil {
+			t.Errorf("Expected error encoding %s", n)
+		}
+	}
+
+	decode := map[string]struct {
+		Base Path
+		TLV  []byte
+	}{
+		"truncated":         {Path{3, 0}, testDeviceTLV[:10]},
+		"truncated header":  {Path{3, 0}, []byte{0xe8, 0x00}},
+		"mismatching ID":    {Path{3, 0, 8}, testDeviceTLV[39:42]},
+		"unexpected type":   {Path{3}, testDeviceTLV},
+		"invalid integer":   {Path{3, 0}, []byte{0xc3, 0x09, 0x00, 0x00, 0x00}},
+		"invalid boolean":   {Path{32800, 0}, []byte{0xc1, 0x01, 0x02}},
+		"invalid float":     {Path{3303, 0}, []byte{0xe2, 0x16, 0x44, 0x00, 0x00}},
+		"nested instance":   {Path{3}, []byte{0x03, 0x00, 0x00, 0x00, 0x00}},
+		"resource instance": {Path{3, 0}, []byte{0x41, 0x00, 0x00}},
+	}
+	for n, test := range decode {
+		if _, err := DecodeTLV(test.Base, test.TLV); err == nil {
+			t.Errorf("Expected error decoding %s", n)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package lwm2m
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/silkeh/senml"
+)
+
+// resourceType returns the registered type of the resource referred to by a path,
+// or the given default if the resource is unknown or has no type.
+func resourceType(p Path, def ResourceType) ResourceType {
+	if r, ok := LookupResource(p); ok && r.Type != TypeNone {
+		return r.Type
+	}
+	return def
+}
+
+// measurementValue returns the value of a measurement for the resource referred to by its path.
+// Numeric values are converted to the unit of the resource, and returned as
+// int64 or uint64 for integer and time resources, and as float64 otherwise.
+// Object links are returned as ObjectLink.
+func measurementValue(p Path, m senml.Measurement) (interface{}, error) {
+	switch v := m.(type) {
+	case *senml.Value:
+		return numericValue(p, v.Value, v.Unit)
+	case *senml.Sum:
+		return numericValue(p, v.Value, v.Unit)
+	case *senml.String:
+		return v.Value, nil
+	case *senml.Boolean:
+		return v.Value, nil
+	case *senml.Data:
+		return v.Value, nil
+	case *senml.ObjectLink:
+		return ParseObjectLink(v.Value)
+	default:
+		return nil, fmt.Errorf("unsupported measurement type %T", m)
+	}
+}
+
+// numericValue returns a numeric value for the resource referred to by a path.
+func numericValue(p Path, f float64, unit senml.Unit) (interface{}, error) {
+	r, ok := LookupResource(p)
+	if ok && r.Unit != senml.None && unit != senml.None && unit != r.Unit {
+		var err error
+		if f, err = unit.Convert(f, r.Unit); err != nil {
+			return nil, fmt.Errorf("resource %s: %w", p, err)
+		}
+	}
+
+	switch r.Type {
+	case TypeInteger, TypeTime:
+		if f < math.MinInt64 || f >= math.MaxInt64 {
+			return nil, fmt.Errorf("resource %s: value %g out of range", p, f)
+		}
+		return int64(math.Round(f)), nil
+	case TypeUnsignedInteger:
+		if f < 0 || f >= math.MaxUint64 {
+			return nil, fmt.Errorf("resource %s: value %g out of range", p, f)
+		}
+		return uint64(math.Round(f)), nil
+	default:
+		return f, nil
+	}
+}
+
+// valueRecord returns the record for a resource value.
+// The unit of the record is the unit of the registered resource.
+// It returns false for empty values, which cannot be represented in SenML.
+func valueRecord(p Path, v interface{}) (senml.Record, bool) {
+	r := senml.Record{Name: p.String()}
+	if res, ok := LookupResource(p); ok {
+		r.Unit = string(res.Unit)
+	}
+
+	switch v := v.(type) {
+	case int64:
+		r.Value = v
+	case uint64:
+		r.Value = v
+	case float64:
+		r.Value = v
+	case bool:
+		r.BooleanValue = &v
+	case string:
+		if resourceType(p, TypeString) == TypeObjectLink {
+			r.ObjectLink = v
+		} else {
+			r.StringValue = v
+		}
+	case []byte:
+		r.DataValue = v
+	case ObjectLink:
+		r.ObjectLink = v.String()
+	}
+
+	if r.Value == nil && r.BooleanValue == nil && r.StringValue == "" && len(r.DataValue) == 0 && r.ObjectLink == "" {
+		return r, false
+	}
+	return r, true
+}
@@ -0,0 +1,275 @@
+package senml
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Measurement kinds that can be set using the kind option of a senml struct tag.
+const (
+	kindValue      = "value"
+	kindSum        = "sum"
+	kindString     = "string"
+	kindBoolean    = "boolean"
+	kindData       = "data"
+	kindObjectLink = "objectlink"
+)
+
+// nameSeparator separates the names of nested structs.
+const nameSeparator = ":"
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	quantityType = reflect.TypeOf(Quantity{})
+	bytesType    = reflect.TypeOf([]byte(nil))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// isMeasurementType returns true if t or a pointer to t implements Measurement.
+func isMeasurementType(t reflect.Type) bool {
+	return t.Implements(measurementType) || reflect.PtrTo(t).Implements(measurementType)
+}
+
+// fieldTag represents a parsed senml struct tag.
+type fieldTag struct {
+	Name string
+	Unit Unit
+	Kind string
+	Skip bool
+}
+
+// parseFieldTag parses the senml struct tag of a struct field.
+// The tag has the format: "name,unit=Cel,kind=sum".
+// The field name is used if no name is given, and a name of "-" skips the field.
+func parseFieldTag(f reflect.StructField) (t fieldTag, err error) {
+	tag, ok := f.Tag.Lookup("senml")
+	parts := strings.Split(tag, ",")
+
+	t.Name = parts[0]
+	if t.Name == "-" && len(parts) == 1 {
+		t.Skip = true
+		return
+	}
+	if !ok || t.Name == "" {
+		t.Name = f.Name
+	}
+
+	for _, p := range parts[1:] {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 {
+			return t, fmt.Errorf("invalid option %q in tag of field %s", p, f.Name)
+		}
+		switch kv[0] {
+		case "unit":
+			t.Unit = Unit(kv[1])
+		case "kind":
+			switch kv[1] {
+			case kindValue, kindSum, kindString, kindBoolean, kindData, kindObjectLink:
+				t.Kind = kv[1]
+			default:
+				return t, fmt.Errorf("invalid kind %q in tag of field %s", kv[1], f.Name)
+			}
+		default:
+			return t, fmt.Errorf("unknown option %q in tag of field %s", kv[0], f.Name)
+		}
+	}
+
+	return
+}
+
+// Marshal returns the measurements for the fields of a struct,
+// using the given base name and time for each measurement.
+//
+// The name and unit of each measurement are configured using a senml struct tag,
+// eg: `senml:"temperature,unit=Cel"`. The field name is used if no name is given,
+// and fields tagged with "-" are skipped.
+// The type of measurement is determined by the type of the field:
+// numeric types result in a Value, bool in a Boolean, string in a String and
+// []byte in Data. This can be overridden using the kind option,
+// eg: `senml:"energy,unit=kWh,kind=sum"`, or `senml:"sensor,kind=objectlink"`
+// for an ObjectLink.
+// A time.Duration results in a Value in seconds,
+// and a Quantity results in a Value with the unit of the quantity.
+// A Measurement field (eg: *Value) results in a copy of the measurement with the
+// name of the field. Other time.Time fields are not supported.
+//
+// Nested structs extend the name of their fields with their own name and a colon.
+// Embedded structs without a tag are flattened.
+// Nil pointers are skipped.
+func Marshal(v interface{}, baseName string, t time.Time) ([]Measurement, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot marshal %T: not a struct", v)
+	}
+
+	var list []Measurement
+	err := marshalStruct(&list, rv, baseName, t)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// marshalStruct appends the measurements for the fields of a struct to a list.
+func marshalStruct(list *[]Measurement, rv reflect.Value, prefix string, t time.Time) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+
+		tag, err := parseFieldTag(f)
+		if err != nil {
+			return err
+		}
+		if tag.Skip {
+			continue
+		}
+
+		fv := rv.Field(i)
+		switch {
+		case isMeasurementType(f.Type):
+			if f.PkgPath != "" {
+				continue
+			}
+			if m := marshalMeasurement(fv, prefix+tag.Name); m != nil {
+				*list = append(*list, m)
+			}
+			continue
+		case indirectType(f.Type) == timeType:
+			return fmt.Errorf("field %s: unsupported type %s", f.Name, f.Type)
+		}
+
+		for fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				break
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Ptr {
+			continue
+		}
+
+		if fv.Kind() == reflect.Struct && fv.Type() != quantityType {
+			name := prefix + tag.Name + nameSeparator
+			if _, ok := f.Tag.Lookup("senml"); f.Anonymous && !ok {
+				name = prefix
+			}
+			if err := marshalStruct(list, fv, name, t); err != nil {
+				return err
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+
+		m, err := marshalField(fv, tag, prefix+tag.Name, t)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		*list = append(*list, m)
+	}
+
+	return nil
+}
+
+// indirectType returns the type pointed to by a (nested) pointer type.
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// marshalMeasurement returns a copy of the measurement in a field with the given name.
+// Nil is returned if the field is nil.
+func marshalMeasurement(fv reflect.Value, name string) Measurement {
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+
+	c := reflect.New(fv.Type())
+	c.Elem().Set(fv)
+	m := c.Interface().(Measurement)
+	m.Attrs().Name = name
+	return m
+}
+
+// marshalField returns the measurement for a single field.
+func marshalField(fv reflect.Value, tag fieldTag, name string, t time.Time) (Measurement, error) {
+	switch fv.Type() {
+	case durationType:
+		unit := tag.Unit
+		if unit == None {
+			unit = Second
+		}
+		f, err := Second.Convert(time.Duration(fv.Int()).Seconds(), unit)
+		if err != nil {
+			return nil, err
+		}
+		return numericMeasurement(name, f, unit, tag.Kind, t)
+	case quantityType:
+		q := NewQuantity(fv.FieldByName("Value").Float(), Unit(fv.FieldByName("Unit").String()))
+		if tag.Unit != None {
+			var err error
+			if q, err = q.Convert(tag.Unit); err != nil {
+				return nil, err
+			}
+		}
+		return numericMeasurement(name, q.Value, q.Unit, tag.Kind, t)
+	case bytesType:
+		if tag.Kind != "" && tag.Kind != kindData {
+			return nil, fmt.Errorf("invalid kind %q for type %s", tag.Kind, fv.Type())
+		}
+		return NewData(name, fv.Bytes(), tag.Unit, t, 0), nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return numericMeasurement(name, float64(fv.Int()), tag.Unit, tag.Kind, t)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return numericMeasurement(name, float64(fv.Uint()), tag.Unit, tag.Kind, t)
+	case reflect.Float32, reflect.Float64:
+		return numericMeasurement(name, fv.Float(), tag.Unit, tag.Kind, t)
+	case reflect.Bool:
+		if tag.Kind != "" && tag.Kind != kindBoolean {
+			return nil, fmt.Errorf("invalid kind %q for type %s", tag.Kind, fv.Type())
+		}
+		return NewBoolean(name, fv.Bool(), tag.Unit, t, 0), nil
+	case reflect.String:
+		switch tag.Kind {
+		case "", kindString:
+			return NewString(name, fv.String(), tag.Unit, t, 0), nil
+		case kindData:
+			return NewData(name, []byte(fv.String()), tag.Unit, t, 0), nil
+		case kindObjectLink:
+			return NewObjectLink(name, fv.String(), tag.Unit, t, 0), nil
+		default:
+			return nil, fmt.Errorf("invalid kind %q for type %s", tag.Kind, fv.Type())
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %s", fv.Type())
+	}
+}
+
+// numericMeasurement returns a Value or Sum measurement depending on the kind.
+func numericMeasurement(name string, f float64, unit Unit, kind string, t time.Time) (Measurement, error) {
+	switch kind {
+	case "", kindValue:
+		return NewValue(name, f, unit, t, 0), nil
+	case kindSum:
+		return NewSum(name, f, unit, t, 0), nil
+	default:
+		return nil, fmt.Errorf("invalid kind %q for numeric value", kind)
+	}
+}
@@ -0,0 +1,133 @@
+package senml
+
+import (
+	"testing"
+	"time"
+)
+
+type testLocation struct {
+	Latitude  float64 `senml:"lat,unit=lat"`
+	Longitude float64 `senml:"lon,unit=lon"`
+}
+
+type testCommon struct {
+	Label string `senml:"label"`
+}
+
+type testDevice struct {
+	testCommon
+	Temperature float64       `senml:"temperature,unit=Cel"`
+	Humidity    float32       `senml:"humidity,unit=%RH"`
+	Energy      uint64        `senml:"energy,unit=kWh,kind=sum"`
+	Open        bool          `senml:"open"`
+	Payload     []byte        `senml:"payload"`
+	Sensor      string        `senml:"sensor,kind=objectlink"`
+	Uptime      time.Duration `senml:"uptime"`
+	Power       Quantity      `senml:"power,unit=W"`
+	Count       int
+	Location    testLocation `senml:"location"`
+	Optional    *float64     `senml:"optional"`
+	Ignored     string       `senml:"-"`
+	internal    int
+}
+
+func TestMarshal(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	dev := testDevice{
+		testCommon:  testCommon{Label: "Machine Room"},
+		Temperature: 23.5,
+		Humidity:    40,
+		Energy:      1200,
+		Open:        true,
+		Payload:     []byte{1, 2},
+		Sensor:      "3303:0",
+		Uptime:      90 * time.Second,
+		Power:       NewQuantity(1.5, Kilowatt),
+		Count:       3,
+		Location:    testLocation{Latitude: 52.1, Longitude: 5.1},
+		Ignored:     "ignored",
+		internal:    1,
+	}
+
+	list, err := Marshal(&dev, "dev:", now)
+	if err != nil {
+		t.Fatalf("Error marshalling: %s", err)
+	}
+
+	exp := []Measurement{
+		NewString("dev:label", "Machine Room", None, now, 0),
+		NewValue("dev:temperature", 23.5, Celsius, now, 0),
+		NewValue("dev:humidity", 40, RelativeHumidityPercent, now, 0),
+		NewSum("dev:energy", 1200, KilowattHour, now, 0),
+		NewBoolean("dev:open", true, None, now, 0),
+		NewData("dev:payload", []byte{1, 2}, None, now, 0),
+		NewObjectLink("dev:sensor", "3303:0", None, now, 0),
+		NewValue("dev:uptime", 90, Second, now, 0),
+		NewValue("dev:power", 1500, Watt, now, 0),
+		NewValue("dev:Count", 3, None, now, 0),
+		NewValue("dev:location:lat", 52.1, Latitude, now, 0),
+		NewValue("dev:location:lon", 5.1, Longitude, now, 0),
+	}
+	if len(list) != len(exp) || !equal(list, exp) {
+		t.Errorf("Marshal incorrect, got:\n%s\nexpected:\n%s", toString(list), toString(exp))
+	}
+}
+
+func TestMarshalMeasurements(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	last := NewValue("temperature", 23.5, Celsius, time.Unix(1555487588, 0), 0)
+	v := struct {
+		Last    *Value      `senml:"last"`
+		State   Measurement `senml:"state"`
+		Missing *Value      `senml:"missing"`
+	}{Last: last, State: NewString("", "ok", None, now, 0)}
+
+	list, err := Marshal(&v, "dev:", now)
+	if err != nil {
+		t.Fatalf("Error marshalling: %s", err)
+	}
+
+	exp := []Measurement{
+		NewValue("dev:last", 23.5, Celsius, time.Unix(1555487588, 0), 0),
+		NewString("dev:state", "ok", None, now, 0),
+	}
+	if len(list) != len(exp) || !equal(list, exp) {
+		t.Errorf("Marshal incorrect, got:\n%s\nexpected:\n%s", toString(list), toString(exp))
+	}
+	if last.Name != "temperature" {
+		t.Errorf("Marshal modified the measurement: %s", last.Name)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	tests := map[string]interface{}{
+		"Not a struct": 5,
+		"Unsupported type": struct {
+			A []int `senml:"a"`
+		}{},
+		"Invalid kind": struct {
+			A bool `senml:"a,kind=sum"`
+		}{},
+		"Unknown kind": struct {
+			A float64 `senml:"a,kind=foo"`
+		}{},
+		"Unknown option": struct {
+			A float64 `senml:"a,foo=bar"`
+		}{},
+		"Time": struct {
+			A time.Time `senml:"a"`
+		}{},
+		"Time pointer": struct {
+			A *time.Time `senml:"a"`
+		}{},
+		"Incompatible unit": struct {
+			A Quantity `senml:"a,unit=m"`
+		}{NewQuantity(1, Watt)},
+	}
+
+	for n, v := range tests {
+		if _, err := Marshal(v, "", time.Time{}); err == nil {
+			t.Errorf("Expected error for %s", n)
+		}
+	}
+}
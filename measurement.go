@@ -7,7 +7,7 @@ import (
 
 // Measurement represents a single SenML measurement value.
 // This interface is meant to represent the various Measurement values,
-// see: Value, Sum, String, Boolean and Data.
+// see: Value, Sum, String, Boolean, Data and ObjectLink.
 type Measurement interface {
 	// Attrs returns a pointer to the measurement of the Measurement value.
 	Attrs() *Attributes
@@ -231,3 +231,40 @@ func (v *Data) Record() Record {
 	s.DataValue = v.Value
 	return s
 }
+
+// ObjectLink represents a measurement value containing an OMA LwM2M object link,
+// eg: "3303:0" for instance 0 of object 3303.
+// It implements Measurement.
+type ObjectLink struct {
+	Attributes
+	Value string
+}
+
+// NewObjectLink returns a new ObjectLink value with the corresponding value and attributes.
+func NewObjectLink(name string, value string, unit Unit, time time.Time, updateTime time.Duration) *ObjectLink {
+	return &ObjectLink{
+		Attributes: Attributes{
+			Name:       name,
+			Unit:       unit,
+			Time:       time,
+			UpdateTime: updateTime,
+		},
+		Value: value,
+	}
+}
+
+// Equal returns true if the given Measurement value is equal.
+func (v *ObjectLink) Equal(ml Measurement) bool {
+	b, ok := ml.(*ObjectLink)
+	if !ok {
+		return false
+	}
+	return v.Attributes.Equal(&b.Attributes) && v.Value == b.Value
+}
+
+// Record returns a SenML record representing the value.
+func (v *ObjectLink) Record() Record {
+	s := v.Attributes.Record()
+	s.ObjectLink = v.Value
+	return s
+}
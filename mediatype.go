@@ -0,0 +1,121 @@
+package senml
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+)
+
+// Media types of SenML packs and SensML streams, as registered in RFC 8428 and RFC 8790.
+const (
+	MediaTypeJSON       = "application/senml+json"
+	MediaTypeCBOR       = "application/senml+cbor"
+	MediaTypeXML        = "application/senml+xml"
+	MediaTypeEXI        = "application/senml-exi"
+	MediaTypeSensMLJSON = "application/sensml+json"
+	MediaTypeSensMLCBOR = "application/sensml+cbor"
+	MediaTypeSensMLXML  = "application/sensml+xml"
+	MediaTypeSensMLEXI  = "application/sensml-exi"
+	MediaTypeEtchJSON   = "application/senml-etch+json"
+	MediaTypeEtchCBOR   = "application/senml-etch+cbor"
+)
+
+// ContentFormat represents a CoAP Content-Format identifier.
+type ContentFormat uint16
+
+// CoAP Content-Formats of SenML packs and SensML streams, as registered in RFC 8428 and RFC 8790.
+const (
+	ContentFormatJSON       ContentFormat = 110
+	ContentFormatSensMLJSON ContentFormat = 111
+	ContentFormatCBOR       ContentFormat = 112
+	ContentFormatSensMLCBOR ContentFormat = 113
+	ContentFormatEXI        ContentFormat = 114
+	ContentFormatSensMLEXI  ContentFormat = 115
+	ContentFormatXML        ContentFormat = 310
+	ContentFormatSensMLXML  ContentFormat = 311
+	ContentFormatEtchJSON   ContentFormat = 320
+	ContentFormatEtchCBOR   ContentFormat = 322
+)
+
+// contentFormats contains the Content-Formats of the media types.
+var contentFormats = map[string]ContentFormat{
+	MediaTypeJSON:       ContentFormatJSON,
+	MediaTypeSensMLJSON: ContentFormatSensMLJSON,
+	MediaTypeCBOR:       ContentFormatCBOR,
+	MediaTypeSensMLCBOR: ContentFormatSensMLCBOR,
+	MediaTypeEXI:        ContentFormatEXI,
+	MediaTypeSensMLEXI:  ContentFormatSensMLEXI,
+	MediaTypeXML:        ContentFormatXML,
+	MediaTypeSensMLXML:  ContentFormatSensMLXML,
+	MediaTypeEtchJSON:   ContentFormatEtchJSON,
+	MediaTypeEtchCBOR:   ContentFormatEtchCBOR,
+}
+
+// LookupContentFormat returns the Content-Format of a media type.
+// Parameters of the media type are ignored.
+func LookupContentFormat(mediaType string) (ContentFormat, bool) {
+	c, ok := contentFormats[baseMediaType(mediaType)]
+	return c, ok
+}
+
+// MediaType returns the media type of the Content-Format,
+// or an empty string if the Content-Format is not a SenML format.
+func (c ContentFormat) MediaType() string {
+	for t, f := range contentFormats {
+		if f == c {
+			return t
+		}
+	}
+	return ""
+}
+
+// baseMediaType returns the media type without parameters.
+func baseMediaType(mediaType string) string {
+	t, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return mediaType
+	}
+	return t
+}
+
+// DecodeMediaType decodes a list of measurements using the codec for the given media type.
+func DecodeMediaType(mediaType string, b []byte) ([]Measurement, error) {
+	c, ok := LookupMediaType(mediaType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported media type %q", mediaType)
+	}
+
+	records, err := c.Decode(b)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(records)
+}
+
+// EncodeMediaType encodes a list of measurements using the codec for the given media type.
+func EncodeMediaType(mediaType string, list []Measurement) ([]byte, error) {
+	c, ok := LookupMediaType(mediaType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported media type %q", mediaType)
+	}
+	return c.Encode(Encode(list))
+}
+
+// Detect returns the media type of an encoded pack based on its first bytes.
+// It distinguishes JSON, CBOR and XML, and returns an error for other data.
+// Leading whitespace is ignored for all formats, and the data is not validated.
+func Detect(b []byte) (string, error) {
+	t := bytes.TrimLeft(b, " \t\r\n")
+	switch {
+	case len(t) == 0:
+		return "", fmt.Errorf("no data")
+	case t[0] == '[':
+		return MediaTypeJSON, nil
+	case t[0] == '<':
+		return MediaTypeXML, nil
+	case t[0]>>5 == 4: // CBOR array
+		return MediaTypeCBOR, nil
+	default:
+		return "", fmt.Errorf("unknown format")
+	}
+}
@@ -0,0 +1,73 @@
+package senml
+
+import "testing"
+
+func TestContentFormats(t *testing.T) {
+	for mt, cf := range contentFormats {
+		if c, ok := LookupContentFormat(mt + "; charset=utf-8"); !ok || c != cf {
+			t.Errorf("Content-Format of %q incorrect, got %v", mt, c)
+		}
+		if m := cf.MediaType(); m != mt {
+			t.Errorf("Media type of %v incorrect, got %q, expected %q", cf, m, mt)
+		}
+	}
+
+	if _, ok := LookupContentFormat("application/json"); ok {
+		t.Errorf("Unexpected Content-Format for %q", "application/json")
+	}
+	if m := ContentFormat(50).MediaType(); m != "" {
+		t.Errorf("Unexpected media type for Content-Format 50: %q", m)
+	}
+}
+
+func TestMediaTypeExamples(t *testing.T) {
+	AutoTime = false
+	for n, example := range testVectors {
+		for _, mt := range []string{MediaTypeJSON, MediaTypeSensMLCBOR, MediaTypeXML} {
+			b, err := EncodeMediaType(mt, example.Result)
+			if err != nil {
+				t.Errorf("Error encoding %s as %s: %s", n, mt, err)
+				continue
+			}
+
+			d, err := Detect(b)
+			if err != nil {
+				t.Errorf("Error detecting %s as %s: %s", n, mt, err)
+			}
+
+			res, err := DecodeMediaType(d, b)
+			if err != nil {
+				t.Errorf("Error decoding %s as %s: %s", n, d, err)
+				continue
+			}
+			if !equal(res, example.Result) {
+				t.Errorf("Decode for example %s as %s incorrect, got:\n%s\nexpected:\n%s", n, mt, toString(res), toString(example.Result))
+			}
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	for b, exp := range map[string]string{" [{}]": MediaTypeJSON, "\n<senml/>": MediaTypeXML, " \x81\xa0": MediaTypeCBOR} {
+		if mt, err := Detect([]byte(b)); err != nil || mt != exp {
+			t.Errorf("Detection of %q incorrect, got %q (%v), expected %q", b, mt, err, exp)
+		}
+	}
+}
+
+func TestMediaTypeErrors(t *testing.T) {
+	for _, mt := range []string{"application/json", MediaTypeEXI, MediaTypeEtchJSON} {
+		if _, err := EncodeMediaType(mt, nil); err == nil {
+			t.Errorf("Expected error encoding %s", mt)
+		}
+		if _, err := DecodeMediaType(mt, []byte("[]")); err == nil {
+			t.Errorf("Expected error decoding %s", mt)
+		}
+	}
+
+	for _, b := range []string{"", " \n", "{}", "foo"} {
+		if mt, err := Detect([]byte(b)); err == nil {
+			t.Errorf("Expected error detecting %q, got %q", b, mt)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Packet types of MQTT 3.1.1.
+const (
+	packetConnect     = 1
+	packetPublish     = 3
+	packetPubAck      = 4
+	packetPubRec      = 5
+	packetPubRel      = 6
+	packetSubscribe   = 8
+	packetUnsubscribe = 10
+	packetPingReq     = 12
+	packetDisconnect  = 14
+)
+
+// publication is a message published to the broker.
+type publication struct {
+	Topic   string
+	QoS     byte
+	Retain  bool
+	Payload []byte
+}
+
+// broker is a minimal in-process MQTT 3.1.1 broker for testing.
+// Messages are forwarded to subscribers with QoS 0.
+type broker struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mutex         sync.Mutex
+	conns         map[*brokerConn]bool
+	publications  []publication
+	subscriptions map[string]byte
+}
+
+// brokerConn is a client connection to the broker.
+type brokerConn struct {
+	net.Conn
+	mutex   sync.Mutex
+	filters map[string]bool
+}
+
+// write writes a packet to the connection.
+func (c *brokerConn) write(header byte, body []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	b := []byte{header}
+	n := len(body)
+	for {
+		d := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			d |= 0x80
+		}
+		b = append(b, d)
+		if n == 0 {
+			break
+		}
+	}
+	c.Write(append(b, body...))
+}
+
+// testBroker starts a broker on the loopback interface.
+func testBroker(t *testing.T) (*broker, string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err)
+	}
+
+	b := &broker{listener: l, conns: make(map[*brokerConn]bool), subscriptions: make(map[string]byte)}
+	b.wg.Add(1)
+	go b.serve()
+
+	return b, "tcp://" + l.Addr().String()
+}
+
+// Close stops the broker and closes all connections.
+func (b *broker) Close() {
+	b.listener.Close()
+	b.mutex.Lock()
+	for c := range b.conns {
+		c.Close()
+	}
+	b.mutex.Unlock()
+	b.wg.Wait()
+}
+
+// Publications returns the messages published to the broker.
+func (b *broker) Publications() []publication {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]publication(nil), b.publications...)
+}
+
+// Subscriptions returns the requested QoS of every subscribed topic filter.
+func (b *broker) Subscriptions() map[string]byte {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	subs := make(map[string]byte, len(b.subscriptions))
+	for f, q := range b.subscriptions {
+		subs[f] = q
+	}
+	return subs
+}
+
+func (b *broker) serve() {
+	defer b.wg.Done()
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		c := &brokerConn{Conn: conn, filters: make(map[string]bool)}
+		b.mutex.Lock()
+		b.conns[c] = true
+		b.mutex.Unlock()
+
+		b.wg.Add(1)
+		go b.handle(c)
+	}
+}
+
+// handle handles the packets of a connection.
+func (b *broker) handle(c *brokerConn) {
+	defer b.wg.Done()
+	defer func() {
+		b.mutex.Lock()
+		delete(b.conns, c)
+		b.mutex.Unlock()
+		c.Close()
+	}()
+
+	r := bufio.NewReader(c)
+	for {
+		header, body, err := readPacket(r)
+		if err != nil {
+			return
+		}
+
+		switch header >> 4 {
+		case packetConnect:
+			c.write(0x20, []byte{0, 0})
+		case packetPublish:
+			b.publish(c, header, body)
+		case packetPubRel:
+			c.write(0x70, body[:2])
+		case packetSubscribe:
+			ack := append([]byte(nil), body[:2]...)
+			for s := body[2:]; len(s) > 2; {
+				n := int(binary.BigEndian.Uint16(s))
+				filter, qos := string(s[2:2+n]), s[2+n]
+				s = s[3+n:]
+
+				b.mutex.Lock()
+				b.subscriptions[filter] = qos
+				c.filters[filter] = true
+				b.mutex.Unlock()
+				ack = append(ack, qos)
+			}
+			c.write(0x90, ack)
+		case packetUnsubscribe:
+			for s := body[2:]; len(s) > 2; {
+				n := int(binary.BigEndian.Uint16(s))
+				b.mutex.Lock()
+				delete(c.filters, string(s[2:2+n]))
+				b.mutex.Unlock()
+				s = s[2+n:]
+			}
+			c.write(0xb0, body[:2])
+		case packetPingReq:
+			c.write(0xd0, nil)
+		case packetDisconnect:
+			return
+		}
+	}
+}
+
+// publish handles a published message, and forwards it to all subscribers.
+func (b *broker) publish(c *brokerConn, header byte, body []byte) {
+	n := int(binary.BigEndian.Uint16(body))
+	p := publication{Topic: string(body[2 : 2+n]), QoS: (header >> 1) & 3, Retain: header&1 == 1}
+	body = body[2+n:]
+
+	switch p.QoS {
+	case 1:
+		c.write(packetPubAck<<4, body[:2])
+		body = body[2:]
+	case 2:
+		c.write(packetPubRec<<4, body[:2])
+		body = body[2:]
+	}
+	p.Payload = body
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.publications = append(b.publications, p)
+
+	fwd := append([]byte{byte(n >> 8), byte(n)}, p.Topic...)
+	fwd = append(fwd, p.Payload...)
+	for s := range b.conns {
+		for f := range s.filters {
+			if topicMatch(f, p.Topic) {
+				s.write(packetPublish<<4, fwd)
+				break
+			}
+		}
+	}
+}
+
+// readPacket reads the fixed header and body of a packet.
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var n, shift int
+	for {
+		d, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(d&0x7f) << shift
+		shift += 7
+		if d&0x80 == 0 {
+			break
+		}
+	}
+
+	body := make([]byte, n)
+	_, err = io.ReadFull(r, body)
+	return header, body, err
+}
+
+// topicMatch returns true if a topic matches a topic filter.
+func topicMatch(filter, topic string) bool {
+	fs, ts := strings.Split(filter, "/"), strings.Split(topic, "/")
+	for i, f := range fs {
+		switch {
+		case f == "#":
+			return true
+		case i >= len(ts):
+			return false
+		case f != "+" && f != ts[i]:
+			return false
+		}
+	}
+	return len(fs) == len(ts)
+}
@@ -0,0 +1,86 @@
+// Package mqtt provides a publisher and subscriber for SenML packs over MQTT.
+// It is built on the github.com/eclipse/paho.mqtt.golang package.
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/silkeh/senml"
+)
+
+// Default values of the Publisher and Subscriber.
+const (
+	DefaultTopicTemplate = "senml/{bn}"
+	DefaultMediaType     = senml.MediaTypeJSON
+	DefaultQoS           = 1
+)
+
+// Placeholders in a topic template.
+const (
+	// PlaceholderBaseName is replaced by the base name of the pack,
+	// without trailing separators.
+	PlaceholderBaseName = "{bn}"
+
+	// PlaceholderCodec is replaced by the name of the codec, eg: "json".
+	PlaceholderCodec = "{codec}"
+)
+
+// Topic returns the topic for a pack from a topic template and codec name.
+// The base name is the base name of the first record, or the name of the
+// record in a single record pack. Trailing separators (':' and '/') are removed.
+// An error is returned if the template contains a base name placeholder
+// and the base name is empty.
+func Topic(template, codec string, records []senml.Record) (string, error) {
+	var bn string
+	if len(records) > 0 {
+		bn = records[0].BaseName
+		if len(records) == 1 {
+			bn += records[0].Name
+		}
+	}
+	bn = strings.TrimRight(bn, ":/")
+
+	if bn == "" && strings.Contains(template, PlaceholderBaseName) {
+		return "", fmt.Errorf("cannot derive topic from pack without base name")
+	}
+
+	topic := strings.NewReplacer(PlaceholderBaseName, bn, PlaceholderCodec, codec).Replace(template)
+	if topic == "" || strings.ContainsAny(topic, "+#") {
+		return "", fmt.Errorf("invalid topic %q", topic)
+	}
+	return topic, nil
+}
+
+// lookupCodec returns the name and codec for a media type.
+func lookupCodec(mediaType string) (string, senml.Codec, error) {
+	if mediaType == "" {
+		mediaType = DefaultMediaType
+	}
+	for _, n := range senml.Codecs() {
+		if c, ok := senml.LookupCodec(n); ok && c.MediaType() == mediaType {
+			return n, c, nil
+		}
+	}
+	return "", nil, fmt.Errorf("unsupported media type %q", mediaType)
+}
+
+// checkQoS returns an error for an invalid QoS level.
+func checkQoS(qos byte) error {
+	if qos > 2 {
+		return fmt.Errorf("invalid QoS level %d", qos)
+	}
+	return nil
+}
+
+// wait waits for a token to complete, or the context to be done.
+func wait(ctx context.Context, t paho.Token) error {
+	select {
+	case <-t.Done():
+		return t.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
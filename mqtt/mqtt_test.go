@@ -0,0 +1,244 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/silkeh/senml"
+	"github.com/silkeh/senml/internal/senmltest"
+)
+
+var testMeasurements = senmltest.Measurements()
+
+func testClient(t *testing.T, addr, id string) paho.Client {
+	c := paho.NewClient(paho.NewClientOptions().AddBroker(addr).SetClientID(id))
+	if err := wait(context.Background(), c.Connect()); err != nil {
+		t.Fatalf("Error connecting: %s", err)
+	}
+	return c
+}
+
+func TestTopic(t *testing.T) {
+	tests := map[string]struct {
+		Template string
+		Records  []senml.Record
+		Expected string
+	}{
+		"Base name":   {DefaultTopicTemplate, senml.Encode(testMeasurements), "senml/sensor"},
+		"Single name": {DefaultTopicTemplate, senml.Encode(testMeasurements[:1]), "senml/sensor:temperature"},
+		"Codec":       {"devices/{bn}/{codec}", []senml.Record{{BaseName: "urn:dev:mac:0024befffe804ff1/"}}, "devices/urn:dev:mac:0024befffe804ff1/json"},
+		"Static":      {"senml", nil, "senml"},
+	}
+
+	for n, test := range tests {
+		topic, err := Topic(test.Template, "json", test.Records)
+		if err != nil {
+			t.Errorf("Error for %s: %s", n, err)
+			continue
+		}
+		if topic != test.Expected {
+			t.Errorf("Topic for %s incorrect, got:\n%s\nexpected:\n%s", n, topic, test.Expected)
+		}
+	}
+
+	for _, template := range []string{DefaultTopicTemplate, "", "senml/+"} {
+		if _, err := Topic(template, "json", []senml.Record{{Name: "a"}, {Name: "b"}}); err == nil {
+			t.Errorf("Expected error for template %q", template)
+		}
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	b, addr := testBroker(t)
+	defer b.Close()
+
+	client := testClient(t, addr, "publisher")
+	defer client.Disconnect(0)
+
+	p := NewPublisher(client)
+	ctx := context.Background()
+	if err := p.Publish(ctx, testMeasurements); err != nil {
+		t.Fatalf("Error publishing: %s", err)
+	}
+
+	p.TopicTemplate = "{codec}/{bn}"
+	p.MediaType = senml.MediaTypeCBOR
+	p.QoS = 2
+	p.Retain = true
+	if err := p.Publish(ctx, testMeasurements); err != nil {
+		t.Fatalf("Error publishing: %s", err)
+	}
+
+	pubs := b.Publications()
+	if len(pubs) != 2 {
+		t.Fatalf("Expected 2 publications, got %d", len(pubs))
+	}
+
+	expected := []struct {
+		Topic  string
+		QoS    byte
+		Retain bool
+		Codec  string
+	}{
+		{"senml/sensor", DefaultQoS, false, "json"},
+		{"cbor/sensor", 2, true, "cbor"},
+	}
+	for i, exp := range expected {
+		pub := pubs[i]
+		if pub.Topic != exp.Topic || pub.QoS != exp.QoS || pub.Retain != exp.Retain {
+			t.Errorf("Publication %d incorrect, got:\n%s %d %v\nexpected:\n%s %d %v",
+				i, pub.Topic, pub.QoS, pub.Retain, exp.Topic, exp.QoS, exp.Retain)
+		}
+
+		c, _ := senml.LookupCodec(exp.Codec)
+		list, err := decode(c, pub.Payload)
+		if err != nil {
+			t.Errorf("Error decoding publication %d: %s", i, err)
+		} else if !senmltest.Equal(list, testMeasurements) {
+			t.Errorf("Publication %d payload incorrect, got:\n%s", i, pub.Payload)
+		}
+	}
+
+	for n, p := range map[string]*Publisher{
+		"QoS":        {Client: client, QoS: 3},
+		"Media type": {Client: client, MediaType: "text/plain"},
+		"Topic":      {Client: client, TopicTemplate: "+"},
+	} {
+		if err := p.Publish(ctx, testMeasurements); err == nil {
+			t.Errorf("Expected error for invalid %s", n)
+		}
+	}
+}
+
+// received is a message received by a Handler.
+type received struct {
+	Topic string
+	List  []senml.Measurement
+	Err   error
+}
+
+func TestSubscriber(t *testing.T) {
+	b, addr := testBroker(t)
+	defer b.Close()
+
+	pubClient := testClient(t, addr, "publisher")
+	defer pubClient.Disconnect(0)
+	subClient := testClient(t, addr, "subscriber")
+	defer subClient.Disconnect(0)
+
+	ch := make(chan received, 10)
+	handler := func(topic string, list []senml.Measurement, err error) {
+		ch <- received{topic, list, err}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s := NewSubscriber(subClient)
+	if err := s.Subscribe(ctx, "senml/#", handler); err != nil {
+		t.Fatalf("Error subscribing: %s", err)
+	}
+	if q := b.Subscriptions()["senml/#"]; q != DefaultQoS {
+		t.Errorf("Subscription QoS incorrect, got %d, expected %d", q, DefaultQoS)
+	}
+
+	p := NewPublisher(pubClient)
+	for _, mt := range []string{senml.MediaTypeJSON, senml.MediaTypeCBOR, senml.MediaTypeXML} {
+		p.MediaType = mt
+		if err := p.Publish(ctx, testMeasurements); err != nil {
+			t.Fatalf("Error publishing %s: %s", mt, err)
+		}
+
+		select {
+		case r := <-ch:
+			if r.Err != nil {
+				t.Errorf("Error receiving %s: %s", mt, r.Err)
+			} else if r.Topic != "senml/sensor" || !senmltest.Equal(r.List, testMeasurements) {
+				t.Errorf("Received %s incorrect, got %s: %v", mt, r.Topic, r.List)
+			}
+		case <-ctx.Done():
+			t.Fatalf("Timeout receiving %s", mt)
+		}
+	}
+
+	if err := pubClient.Publish("senml/invalid", 1, false, []byte("invalid")).Error(); err != nil {
+		t.Fatalf("Error publishing: %s", err)
+	}
+	select {
+	case r := <-ch:
+		if r.Err == nil {
+			t.Errorf("Expected decoding error, got: %v", r.List)
+		}
+	case <-ctx.Done():
+		t.Fatalf("Timeout receiving invalid message")
+	}
+
+	if err := s.Unsubscribe(ctx, "senml/#"); err != nil {
+		t.Fatalf("Error unsubscribing: %s", err)
+	}
+	if err := p.Publish(ctx, testMeasurements); err != nil {
+		t.Fatalf("Error publishing: %s", err)
+	}
+	select {
+	case r := <-ch:
+		t.Errorf("Received message after unsubscribing: %v", r)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSubscriberMediaType(t *testing.T) {
+	b, addr := testBroker(t)
+	defer b.Close()
+
+	client := testClient(t, addr, "subscriber")
+	defer client.Disconnect(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ch := make(chan error, 1)
+	s := &Subscriber{Client: client, MediaType: senml.MediaTypeCBOR}
+	err := s.Subscribe(ctx, "senml/+", func(_ string, _ []senml.Measurement, err error) { ch <- err })
+	if err != nil {
+		t.Fatalf("Error subscribing: %s", err)
+	}
+
+	p := NewPublisher(client)
+	if err := p.Publish(ctx, testMeasurements); err != nil {
+		t.Fatalf("Error publishing: %s", err)
+	}
+	select {
+	case err := <-ch:
+		if err == nil {
+			t.Errorf("Expected error decoding JSON as CBOR")
+		}
+	case <-ctx.Done():
+		t.Fatalf("Timeout receiving message")
+	}
+
+	for n, s := range map[string]*Subscriber{
+		"QoS":        {Client: client, QoS: 3},
+		"Media type": {Client: client, MediaType: "text/plain"},
+	} {
+		if err := s.Subscribe(ctx, "senml/#", nil); err == nil {
+			t.Errorf("Expected error for invalid %s", n)
+		}
+	}
+}
+
+func ExamplePublisher() {
+	client := paho.NewClient(paho.NewClientOptions().AddBroker("tcp://localhost:1883"))
+	if t := client.Connect(); t.Wait() && t.Error() != nil {
+		fmt.Println(t.Error())
+		return
+	}
+
+	p := NewPublisher(client)
+	p.TopicTemplate = "devices/{bn}/senml"
+	p.Publish(context.Background(), []senml.Measurement{
+		senml.NewValue("urn:dev:ow:10e2073a01080063:temperature", 23.5, senml.Celsius, time.Now(), 0),
+	})
+}
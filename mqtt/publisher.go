@@ -0,0 +1,69 @@
+package mqtt
+
+import (
+	"context"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/silkeh/senml"
+)
+
+// Publisher publishes packs to topics derived from their base names.
+// Packs can be encoded using any registered SenML codec.
+type Publisher struct {
+	// Client is the connected MQTT client used for publishing.
+	Client paho.Client
+
+	// TopicTemplate is the template of the topics, see Topic.
+	// DefaultTopicTemplate is used if this is empty.
+	TopicTemplate string
+
+	// MediaType is the media type used for encoding packs.
+	// DefaultMediaType is used if this is empty.
+	MediaType string
+
+	// QoS is the MQTT quality of service level of published messages.
+	QoS byte
+
+	// Retain enables the retained flag of published messages.
+	Retain bool
+}
+
+// NewPublisher returns a new Publisher using the given client with default settings.
+func NewPublisher(client paho.Client) *Publisher {
+	return &Publisher{
+		Client:        client,
+		TopicTemplate: DefaultTopicTemplate,
+		MediaType:     DefaultMediaType,
+		QoS:           DefaultQoS,
+	}
+}
+
+// Publish publishes a list of measurements as a single pack.
+// It returns when the message is delivered according to the QoS level.
+func (p *Publisher) Publish(ctx context.Context, list []senml.Measurement) error {
+	if err := checkQoS(p.QoS); err != nil {
+		return err
+	}
+
+	name, codec, err := lookupCodec(p.MediaType)
+	if err != nil {
+		return err
+	}
+
+	records := senml.Encode(list)
+	b, err := codec.Encode(records)
+	if err != nil {
+		return err
+	}
+
+	template := p.TopicTemplate
+	if template == "" {
+		template = DefaultTopicTemplate
+	}
+	topic, err := Topic(template, name, records)
+	if err != nil {
+		return err
+	}
+
+	return wait(ctx, p.Client.Publish(topic, p.QoS, p.Retain, b))
+}
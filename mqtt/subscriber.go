@@ -0,0 +1,80 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/silkeh/senml"
+)
+
+// Handler handles the measurements received on a topic,
+// or the error that occurred while decoding the message.
+type Handler func(topic string, list []senml.Measurement, err error)
+
+// Subscriber subscribes to topic filters and decodes the received packs.
+type Subscriber struct {
+	// Client is the connected MQTT client used for subscribing.
+	Client paho.Client
+
+	// MediaType is the media type used for decoding packs.
+	// The format is detected from the payload if this is empty,
+	// which supports JSON, CBOR and XML.
+	MediaType string
+
+	// QoS is the maximum MQTT quality of service level of received messages.
+	QoS byte
+}
+
+// NewSubscriber returns a new Subscriber using the given client with default settings.
+func NewSubscriber(client paho.Client) *Subscriber {
+	return &Subscriber{Client: client, QoS: DefaultQoS}
+}
+
+// Subscribe subscribes to a topic filter, and calls the handler for every
+// received message. It returns when the subscription is acknowledged.
+func (s *Subscriber) Subscribe(ctx context.Context, filter string, h Handler) error {
+	if err := checkQoS(s.QoS); err != nil {
+		return err
+	}
+
+	var codec senml.Codec
+	if s.MediaType != "" {
+		var err error
+		if _, codec, err = lookupCodec(s.MediaType); err != nil {
+			return err
+		}
+	}
+
+	return wait(ctx, s.Client.Subscribe(filter, s.QoS, func(_ paho.Client, m paho.Message) {
+		list, err := decode(codec, m.Payload())
+		h(m.Topic(), list, err)
+	}))
+}
+
+// Unsubscribe removes the subscriptions to the given topic filters.
+func (s *Subscriber) Unsubscribe(ctx context.Context, filters ...string) error {
+	return wait(ctx, s.Client.Unsubscribe(filters...))
+}
+
+// decode decodes the measurements in a payload using the given codec,
+// or the detected format if no codec is given.
+func decode(codec senml.Codec, b []byte) ([]senml.Measurement, error) {
+	if codec == nil {
+		mt, err := senml.Detect(b)
+		if err != nil {
+			return nil, err
+		}
+		c, ok := senml.LookupMediaType(mt)
+		if !ok {
+			return nil, fmt.Errorf("unsupported media type %q", mt)
+		}
+		codec = c
+	}
+
+	records, err := codec.Decode(b)
+	if err != nil {
+		return nil, err
+	}
+	return senml.Decode(records)
+}
@@ -0,0 +1,308 @@
+package prometheus
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/silkeh/senml"
+)
+
+// Metric types that are converted by Decode, in addition to gauges and counters.
+const (
+	typeUntyped = "untyped"
+	typeUnknown = "unknown"
+)
+
+// sampleSuffixes are the suffixes of samples of metric types that are not converted.
+var sampleSuffixes = []string{"_created", "_bucket", "_count", "_sum", "_gcount", "_gsum", "_info"}
+
+// DecodeOptions configures the conversion of metrics to measurements.
+type DecodeOptions struct {
+	// Namespace is removed from the start of metric names, eg: "senml".
+	Namespace string
+
+	// NameTemplate is the template for the names of the measurements.
+	// The placeholder {name} is replaced by the metric name,
+	// and other placeholders by the value of the label with the same name,
+	// eg: "{instance}:{name}".
+	// Separators following a missing label are omitted.
+	// If this is empty, the values of all labels and the metric name
+	// are joined with colons in order of appearance.
+	NameTemplate string
+}
+
+// Decode decodes metrics in the Prometheus text exposition format or the
+// OpenMetrics text format into measurements.
+// Gauges are converted to values and counters to sums, while untyped metrics
+// and metrics without a type are converted to values.
+// Samples of other metric types are ignored.
+// The unit is inferred from the suffix of the metric name, which is removed
+// from the name together with the namespace and the "_total" suffix of counters.
+// Samples without a timestamp result in measurements with a zero time.
+func Decode(b []byte, format Format, opts DecodeOptions) ([]senml.Measurement, error) {
+	var list []senml.Measurement
+	types := make(map[string]string)
+
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "":
+			continue
+		case line == "# EOF":
+			return list, nil
+		case strings.HasPrefix(line, "#"):
+			if f := strings.Fields(line); len(f) == 4 && f[1] == "TYPE" {
+				types[f[2]] = f[3]
+			}
+			continue
+		}
+
+		name, labels, value, t, err := parseSample(line, format)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+
+		typ, ok := sampleType(types, name)
+		if !ok {
+			continue
+		}
+
+		name, unit := opts.metricName(name, typ)
+		name = opts.name(name, labels)
+		if typ == typeCounter {
+			list = append(list, senml.NewSum(name, value, unit, t, 0))
+		} else {
+			list = append(list, senml.NewValue(name, value, unit, t, 0))
+		}
+	}
+
+	return list, s.Err()
+}
+
+// sampleType returns the metric type of a sample, and false if the type is not converted.
+func sampleType(types map[string]string, name string) (string, bool) {
+	if typ, ok := types[name]; ok {
+		switch typ {
+		case typeGauge, typeCounter:
+			return typ, true
+		case typeUntyped, typeUnknown:
+			return typeGauge, true
+		default:
+			return "", false
+		}
+	}
+
+	if types[strings.TrimSuffix(name, "_total")] == typeCounter {
+		return typeCounter, true
+	}
+	for _, suffix := range sampleSuffixes {
+		if _, ok := types[strings.TrimSuffix(name, suffix)]; ok && strings.HasSuffix(name, suffix) {
+			return "", false
+		}
+	}
+
+	return typeGauge, true
+}
+
+// metricName returns the name and the unit of a metric without the namespace and suffixes.
+func (o DecodeOptions) metricName(name, typ string) (string, senml.Unit) {
+	if o.Namespace != "" && strings.HasPrefix(name, o.Namespace+"_") {
+		name = name[len(o.Namespace)+1:]
+	}
+	if typ == typeCounter {
+		name = strings.TrimSuffix(name, "_total")
+	}
+
+	var unit senml.Unit
+	var suffix string
+	for u, s := range unitSuffixes {
+		if len(s) > len(suffix) && len(name) > len(s)+1 && strings.HasSuffix(name, "_"+s) {
+			unit, suffix = u, s
+		}
+	}
+	if suffix != "" {
+		name = name[:len(name)-len(suffix)-1]
+	}
+
+	return name, unit
+}
+
+// name returns the name of a measurement using the name template.
+func (o DecodeOptions) name(metric string, labels []label) string {
+	if o.NameTemplate == "" {
+		parts := make([]string, 0, len(labels)+1)
+		for _, l := range labels {
+			if l.Value != "" {
+				parts = append(parts, l.Value)
+			}
+		}
+		return strings.Join(append(parts, metric), ":")
+	}
+
+	var b strings.Builder
+	tmpl := o.NameTemplate
+	for tmpl != "" {
+		start := strings.IndexByte(tmpl, '{')
+		end := strings.IndexByte(tmpl, '}')
+		if start < 0 || end < start {
+			b.WriteString(tmpl)
+			break
+		}
+		b.WriteString(tmpl[:start])
+
+		key := tmpl[start+1 : end]
+		tmpl = tmpl[end+1:]
+
+		value := metric
+		if key != "name" {
+			value = labelValue(labels, key)
+		}
+		if value == "" {
+			if tmpl != "" && strings.IndexByte(DefaultSeparators, tmpl[0]) >= 0 {
+				tmpl = tmpl[1:]
+			}
+			continue
+		}
+		b.WriteString(value)
+	}
+
+	return strings.TrimRight(b.String(), DefaultSeparators)
+}
+
+// labelValue returns the value of the label with the given name.
+func labelValue(labels []label, name string) string {
+	for _, l := range labels {
+		if l.Name == name {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+// parseSample parses a sample line.
+func parseSample(line string, format Format) (name string, labels []label, value float64, t time.Time, err error) {
+	end := strings.IndexAny(line, "{ \t")
+	if end < 0 {
+		return "", nil, 0, t, fmt.Errorf("missing value in sample %q", line)
+	}
+	name, line = line[:end], line[end:]
+	if name == "" {
+		return "", nil, 0, t, fmt.Errorf("missing metric name in sample %q", line)
+	}
+
+	if line[0] == '{' {
+		if labels, line, err = parseLabels(line[1:]); err != nil {
+			return "", nil, 0, t, fmt.Errorf("invalid labels of %s: %w", name, err)
+		}
+	}
+
+	if i := strings.Index(line, " # "); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 1 || len(fields) > 2 {
+		return "", nil, 0, t, fmt.Errorf("invalid sample of %s: %q", name, line)
+	}
+
+	if value, err = strconv.ParseFloat(fields[0], 64); err != nil {
+		return "", nil, 0, t, fmt.Errorf("invalid value of %s: %q", name, fields[0])
+	}
+	if len(fields) == 2 {
+		if t, err = parseTime(fields[1], format); err != nil {
+			return "", nil, 0, t, fmt.Errorf("invalid timestamp of %s: %q", name, fields[1])
+		}
+	}
+
+	return name, labels, value, t, nil
+}
+
+// parseLabels parses the labels of a sample after the opening brace,
+// returning the labels and the remainder of the line.
+func parseLabels(s string) ([]label, string, error) {
+	var labels []label
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return nil, "", fmt.Errorf("missing '}'")
+		}
+		if s[0] == '}' {
+			return labels, s[1:], nil
+		}
+
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			return nil, "", fmt.Errorf("missing '=' in %q", s)
+		}
+		l := label{Name: strings.TrimSpace(s[:eq])}
+		s = strings.TrimLeft(s[eq+1:], " \t")
+		if s == "" || s[0] != '"' {
+			return nil, "", fmt.Errorf("unquoted value of label %q", l.Name)
+		}
+
+		var err error
+		if l.Value, s, err = parseLabelValue(s[1:]); err != nil {
+			return nil, "", fmt.Errorf("label %q: %w", l.Name, err)
+		}
+		labels = append(labels, l)
+
+		s = strings.TrimLeft(s, " \t")
+		if s != "" && s[0] == ',' {
+			s = s[1:]
+		} else if s == "" || s[0] != '}' {
+			return nil, "", fmt.Errorf("expected ',' or '}' after label %q", l.Name)
+		}
+	}
+}
+
+// parseLabelValue parses an escaped label value after the opening quote,
+// returning the value and the remainder after the closing quote.
+func parseLabelValue(s string) (string, string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return b.String(), s[i+1:], nil
+		case '\\':
+			if i++; i == len(s) {
+				break
+			}
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case '\\', '"':
+				b.WriteByte(s[i])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", "", fmt.Errorf("unterminated value")
+}
+
+// parseTime parses a sample timestamp, in milliseconds for the Prometheus
+// text format and in seconds for OpenMetrics.
+func parseTime(s string, format Format) (time.Time, error) {
+	if format != FormatOpenMetrics {
+		ms, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(0, ms*int64(time.Millisecond)), nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+	}
+	sec := math.Floor(f)
+	return time.Unix(int64(sec), int64(math.Round((f-sec)*1e6))*int64(time.Microsecond)), nil
+}
@@ -0,0 +1,112 @@
+package prometheus
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/silkeh/senml"
+	"github.com/silkeh/senml/internal/senmltest"
+)
+
+func TestDecode(t *testing.T) {
+	tests := map[string]struct {
+		Input   string
+		Format  Format
+		Options DecodeOptions
+		List    []senml.Measurement
+	}{
+		"text": {
+			Input: `# HELP node_cpu_seconds_total Seconds the CPUs spent in each mode.
+# TYPE node_cpu_seconds_total counter
+node_cpu_seconds_total{cpu="0",mode="idle"} 3.4e+06 1555487588500
+# TYPE node_temperature_celsius gauge
+node_temperature_celsius{chip="acpi", sensor="temp1",} 41.5
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 0.05
+rpc_duration_seconds_sum 17
+rpc_duration_seconds_count 340
+up 1
+`,
+			Options: DecodeOptions{Namespace: "node"},
+			List: []senml.Measurement{
+				senml.NewSum("0:idle:cpu", 3.4e6, senml.Second, testTime, 0),
+				senml.NewValue("acpi:temp1:temperature", 41.5, senml.Celsius, time.Time{}, 0),
+				senml.NewValue("up", 1, senml.None, time.Time{}, 0),
+			},
+		},
+		"openmetrics": {
+			Input: `# TYPE energy_kilowatt_hours counter
+# UNIT energy_kilowatt_hours kilowatt_hours
+energy_kilowatt_hours_total{instance="meter-1",job="power"} 1234 1555487588.5 # {trace_id="abc"} 1
+energy_kilowatt_hours_created{instance="meter-1",job="power"} 1555480000
+# TYPE memory_bytes gauge
+memory_bytes{job="power"} 1024
+# EOF
+ignored 1
+`,
+			Format:  FormatOpenMetrics,
+			Options: DecodeOptions{NameTemplate: "{instance}:{job}/{name}"},
+			List: []senml.Measurement{
+				senml.NewSum("meter-1:power/energy", 1234, senml.KilowattHour, testTime, 0),
+				senml.NewValue("power/memory", 1024, senml.Byte, time.Time{}, 0),
+			},
+		},
+		"escapes": {
+			Input:   `temperature{device="a\"b\\c\nd"} -Inf`,
+			Options: DecodeOptions{NameTemplate: "{device}:{name}"},
+			List: []senml.Measurement{
+				senml.NewValue("a\"b\\c\nd:temperature", math.Inf(-1), senml.None, time.Time{}, 0),
+			},
+		},
+	}
+
+	for n, test := range tests {
+		list, err := Decode([]byte(test.Input), test.Format, test.Options)
+		if err != nil {
+			t.Errorf("Error decoding %s: %s", n, err)
+			continue
+		}
+		if !senmltest.Equal(list, test.List) {
+			t.Errorf("Measurements of %s incorrect, got:\n%v\nexpected:\n%v", n, list, test.List)
+		}
+	}
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatText, FormatOpenMetrics} {
+		b, err := Encode(testMeasurements[:3], format, Options{Timestamps: true})
+		if err != nil {
+			t.Fatalf("Error encoding: %s", err)
+		}
+
+		list, err := Decode(b, format, DecodeOptions{})
+		if err != nil {
+			t.Errorf("Error decoding %s: %s", b, err)
+			continue
+		}
+
+		exp := []senml.Measurement{testMeasurements[2], testMeasurements[1], testMeasurements[0]}
+		if !senmltest.Equal(list, exp) {
+			t.Errorf("Measurements of format %v incorrect, got:\n%v\nexpected:\n%v", format, list, exp)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []string{
+		"temperature",
+		"temperature abc",
+		"temperature 1 2 3",
+		`temperature{device="a} 1`,
+		`temperature{device=a} 1`,
+		`temperature{device="a" mode="b"} 1`,
+		"temperature 1 1.5",
+	}
+
+	for _, input := range tests {
+		if _, err := Decode([]byte(input), FormatText, DecodeOptions{}); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
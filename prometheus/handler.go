@@ -0,0 +1,103 @@
+package prometheus
+
+import (
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/silkeh/senml"
+)
+
+// Source returns the measurements to serve for a request.
+type Source func(r *http.Request) ([]senml.Measurement, error)
+
+// Handler serves the latest measurements from a Source as metrics.
+// The OpenMetrics format is used if it is preferred in the Accept header of the request,
+// and the Prometheus text format otherwise.
+type Handler struct {
+	Source  Source
+	Options Options
+
+	// ErrorLog is used for logging errors of the source and the encoder.
+	// The standard logger is used if this is nil.
+	ErrorLog *log.Logger
+}
+
+// NewHandler returns a new Handler serving the measurements from the given source.
+func NewHandler(source Source, opts Options) *Handler {
+	return &Handler{Source: source, Options: opts}
+}
+
+// ServeHTTP serves the measurements from the source.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodHead}, ", "))
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept")
+	format := negotiate(r.Header.Get("Accept"))
+
+	list, err := h.Source(r)
+	if err != nil {
+		h.internalError(w, err)
+		return
+	}
+
+	b, err := Encode(list, format, h.Options)
+	if err != nil {
+		h.internalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(b)
+	}
+}
+
+// internalError logs an error and responds with an Internal Server Error,
+// without exposing the error to the client.
+func (h *Handler) internalError(w http.ResponseWriter, err error) {
+	if h.ErrorLog != nil {
+		h.ErrorLog.Printf("senml/prometheus: internal server error: %s", err)
+	} else {
+		log.Printf("senml/prometheus: internal server error: %s", err)
+	}
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// negotiate returns the format to use for an Accept header.
+// OpenMetrics is used if its quality is at least that of any other accepted media type.
+func negotiate(accept string) Format {
+	var openMetrics, other float64
+	for _, s := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(s)
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		switch {
+		case mt == "application/openmetrics-text" && q > openMetrics:
+			openMetrics = q
+		case mt != "application/openmetrics-text" && q > other:
+			other = q
+		}
+	}
+
+	if openMetrics > 0 && openMetrics >= other {
+		return FormatOpenMetrics
+	}
+	return FormatText
+}
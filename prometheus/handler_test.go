@@ -0,0 +1,77 @@
+package prometheus
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/silkeh/senml"
+)
+
+func testSource(r *http.Request) ([]senml.Measurement, error) {
+	return testMeasurements, nil
+}
+
+func TestHandler(t *testing.T) {
+	tests := map[string]string{
+		"":                             ContentTypeText,
+		"*/*":                          ContentTypeText,
+		"text/plain":                   ContentTypeText,
+		"application/openmetrics-text": ContentTypeOpenMetrics,
+		"application/openmetrics-text;version=1.0.0,application/openmetrics-text;version=0.0.1;q=0.75,text/plain;version=0.0.4;q=0.5,*/*;q=0.1": ContentTypeOpenMetrics,
+		"text/plain, application/openmetrics-text;q=0.5": ContentTypeText,
+		"application/openmetrics-text;q=0":               ContentTypeText,
+	}
+
+	h := NewHandler(testSource, Options{})
+	for accept, ct := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Status for %q incorrect, got %v", accept, rec.Code)
+			continue
+		}
+		if got := rec.Header().Get("Content-Type"); got != ct {
+			t.Errorf("Content-Type for %q incorrect, got %q, expected %q", accept, got, ct)
+		}
+
+		format := FormatText
+		if ct == ContentTypeOpenMetrics {
+			format = FormatOpenMetrics
+		}
+		exp, _ := Encode(testMeasurements, format, Options{})
+		if rec.Body.String() != string(exp) {
+			t.Errorf("Body for %q incorrect, got:\n%s\nexpected:\n%s", accept, rec.Body, exp)
+		}
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewHandler(func(r *http.Request) ([]senml.Measurement, error) {
+		return nil, errors.New("database password rejected")
+	}, Options{})
+	h.ErrorLog = log.New(&buf, "", 0)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "database password rejected") {
+		t.Errorf("Expected Internal Server Error without details, got %v: %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(buf.String(), "database password rejected") {
+		t.Errorf("Internal error not logged, got: %q", buf.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, HEAD" {
+		t.Errorf("Expected Method Not Allowed, got %v", rec.Code)
+	}
+}
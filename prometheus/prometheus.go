@@ -0,0 +1,361 @@
+// Package prometheus converts SenML measurements to and from the Prometheus
+// text exposition format and the OpenMetrics text format.
+//
+// Values are exported as gauges, sums as counters and boolean values as gauges
+// with a value of 0 or 1. Other measurements are ignored.
+// The resolved name of a measurement is split into segments,
+// of which the last is used as the metric name and the others as labels.
+// Decode converts metrics back to measurements.
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/silkeh/senml"
+)
+
+// Content types of the supported formats.
+const (
+	ContentTypeText        = "text/plain; version=0.0.4; charset=utf-8"
+	ContentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// Default values of the Options.
+const (
+	DefaultLabel      = "device"
+	DefaultSeparators = ":/"
+)
+
+// Format represents an exposition format.
+type Format int
+
+// Supported exposition formats.
+const (
+	// FormatText is the Prometheus text exposition format.
+	FormatText Format = iota
+
+	// FormatOpenMetrics is the OpenMetrics text format.
+	FormatOpenMetrics
+)
+
+// ContentType returns the content type of the format.
+func (f Format) ContentType() string {
+	if f == FormatOpenMetrics {
+		return ContentTypeOpenMetrics
+	}
+	return ContentTypeText
+}
+
+// Metric types used in the exposition formats.
+const (
+	typeGauge   = "gauge"
+	typeCounter = "counter"
+)
+
+// Options configures the conversion of measurements to metrics.
+type Options struct {
+	// Namespace is prepended to all metric names, eg: "senml".
+	Namespace string
+
+	// Labels are the names of the labels for the leading segments of a name.
+	// Segments without a label are prepended to the metric name.
+	// If no labels are given, all segments but the last are combined
+	// into a single DefaultLabel.
+	Labels []string
+
+	// Separators are the characters separating the segments of a name.
+	// DefaultSeparators is used if this is empty.
+	Separators string
+
+	// Timestamps enables the timestamps of the samples.
+	Timestamps bool
+}
+
+// label is a label of a sample.
+type label struct {
+	Name, Value string
+}
+
+// sample is a single sample of a metric family.
+type sample struct {
+	Labels []label
+	Value  float64
+	Time   time.Time
+}
+
+// family is a metric family.
+type family struct {
+	Name    string
+	Type    string
+	Unit    string
+	Samples []sample
+	index   map[string]int
+}
+
+// Encode encodes the latest measurement of every series in the given format.
+// Metric families are sorted by name, and samples are listed in order of appearance.
+// An error is returned if a metric name is used for different types of measurements.
+func Encode(list []senml.Measurement, format Format, opts Options) ([]byte, error) {
+	families := make(map[string]*family)
+	for _, m := range list {
+		typ, value, ok := metricValue(m)
+		if !ok {
+			continue
+		}
+
+		a := m.Attrs()
+		name, labels, err := opts.split(a.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		unit := unitSuffix(a.Unit)
+		if unit != "" && !strings.HasSuffix(name, "_"+unit) {
+			name += "_" + unit
+		}
+		if typ == typeCounter {
+			name = strings.TrimSuffix(name, "_total")
+		}
+
+		f, ok := families[name]
+		if !ok {
+			f = &family{Name: name, Type: typ, Unit: unit, index: make(map[string]int)}
+			families[name] = f
+		}
+		if f.Type != typ {
+			return nil, fmt.Errorf("metric %s used as %s and %s", name, f.Type, typ)
+		}
+		f.add(sample{Labels: labels, Value: value, Time: a.Time})
+	}
+
+	names := make([]string, 0, len(families))
+	for n := range families {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, n := range names {
+		families[n].write(&buf, format, opts.Timestamps)
+	}
+	if format == FormatOpenMetrics {
+		buf.WriteString("# EOF\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// metricValue returns the metric type and value of a measurement.
+func metricValue(m senml.Measurement) (typ string, value float64, ok bool) {
+	switch v := m.(type) {
+	case *senml.Value:
+		return typeGauge, v.Value, true
+	case *senml.Sum:
+		return typeCounter, v.Value, true
+	case *senml.Boolean:
+		if v.Value {
+			return typeGauge, 1, true
+		}
+		return typeGauge, 0, true
+	default:
+		return "", 0, false
+	}
+}
+
+// add adds a sample to the family, replacing an older sample of the same series.
+func (f *family) add(s sample) {
+	key := labelString(s.Labels)
+	i, ok := f.index[key]
+	if !ok {
+		f.index[key] = len(f.Samples)
+		f.Samples = append(f.Samples, s)
+		return
+	}
+	if !s.Time.Before(f.Samples[i].Time) {
+		f.Samples[i] = s
+	}
+}
+
+// write writes the metric family in the given format.
+func (f *family) write(buf *bytes.Buffer, format Format, timestamps bool) {
+	name := f.Name
+	if f.Type == typeCounter && format == FormatText {
+		name += "_total"
+	}
+
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, f.Type)
+	if f.Unit != "" && format == FormatOpenMetrics {
+		fmt.Fprintf(buf, "# UNIT %s %s\n", name, f.Unit)
+	}
+
+	sampleName := name
+	if f.Type == typeCounter && format == FormatOpenMetrics {
+		sampleName += "_total"
+	}
+	for _, s := range f.Samples {
+		buf.WriteString(sampleName)
+		buf.WriteString(labelString(s.Labels))
+		buf.WriteByte(' ')
+		buf.WriteString(formatValue(s.Value))
+		if timestamps && !s.Time.IsZero() {
+			buf.WriteByte(' ')
+			buf.WriteString(formatTime(s.Time, format))
+		}
+		buf.WriteByte('\n')
+	}
+}
+
+// split splits a name into a metric name and labels.
+func (o Options) split(name string) (string, []label, error) {
+	seps := o.Separators
+	if seps == "" {
+		seps = DefaultSeparators
+	}
+
+	name = strings.TrimRight(name, seps)
+	segments := strings.FieldsFunc(name, func(r rune) bool { return strings.ContainsRune(seps, r) })
+	if len(segments) == 0 {
+		return "", nil, fmt.Errorf("invalid metric name %q", name)
+	}
+	prefix := segments[:len(segments)-1]
+
+	var labels []label
+	if len(o.Labels) == 0 {
+		if len(prefix) > 0 {
+			device := strings.TrimRight(name[:strings.LastIndexAny(name, seps)], seps)
+			labels = append(labels, label{Name: DefaultLabel, Value: device})
+		}
+		prefix = nil
+	} else {
+		for i, n := range o.Labels {
+			if i >= len(prefix) {
+				break
+			}
+			labels = append(labels, label{Name: sanitize(n), Value: prefix[i]})
+		}
+		if len(prefix) > len(o.Labels) {
+			prefix = prefix[len(o.Labels):]
+		} else {
+			prefix = nil
+		}
+	}
+
+	parts := append([]string{}, prefix...)
+	if o.Namespace != "" {
+		parts = append([]string{o.Namespace}, parts...)
+	}
+	parts = append(parts, segments[len(segments)-1])
+
+	return sanitize(strings.Join(parts, "_")), labels, nil
+}
+
+// sanitize replaces all characters that are invalid in a metric or label name,
+// and prefixes names starting with a digit with an underscore.
+func sanitize(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_':
+		default:
+			b[i] = '_'
+		}
+	}
+	if len(b) > 0 && b[0] >= '0' && b[0] <= '9' {
+		return "_" + string(b)
+	}
+	return string(b)
+}
+
+// labelString returns the labels as they are written in a sample.
+func labelString(labels []label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	strs := make([]string, len(labels))
+	for i, l := range labels {
+		strs[i] = l.Name + `="` + escapeLabelValue(l.Value) + `"`
+	}
+	return "{" + strings.Join(strs, ",") + "}"
+}
+
+// labelEscaper escapes label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabelValue escapes backslashes, double quotes and line feeds in a label value.
+func escapeLabelValue(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// formatValue formats a sample value.
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// formatTime formats a sample timestamp, in milliseconds for the Prometheus
+// text format and in seconds for OpenMetrics.
+func formatTime(t time.Time, format Format) string {
+	if format == FormatOpenMetrics {
+		return strconv.FormatFloat(float64(t.UnixNano())/1e9, 'f', -1, 64)
+	}
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+}
+
+// unitSuffixes contains the metric name suffixes of common units,
+// following the base units and naming used by Prometheus.
+var unitSuffixes = map[senml.Unit]string{
+	senml.Meter:                   "meters",
+	senml.Kilogram:                "kilograms",
+	senml.Gram:                    "grams",
+	senml.Second:                  "seconds",
+	senml.Millisecond:             "milliseconds",
+	senml.Minute:                  "minutes",
+	senml.Hour:                    "hours",
+	senml.Ampere:                  "amperes",
+	senml.Kelvin:                  "kelvin",
+	senml.Celsius:                 "celsius",
+	senml.Hertz:                   "hertz",
+	senml.Newton:                  "newtons",
+	senml.Pascal:                  "pascals",
+	senml.Joule:                   "joules",
+	senml.Watt:                    "watts",
+	senml.Kilowatt:                "kilowatts",
+	senml.WattHour:                "watt_hours",
+	senml.KilowattHour:            "kilowatt_hours",
+	senml.Volt:                    "volts",
+	senml.Lux:                     "lux",
+	senml.Bit:                     "bits",
+	senml.Byte:                    "bytes",
+	senml.Kibibyte:                "kibibytes",
+	senml.Ratio:                   "ratio",
+	senml.Percent:                 "percent",
+	senml.RelativeHumidityPercent: "relative_humidity_percent",
+	senml.Decibel:                 "decibels",
+}
+
+// unitSuffix returns the metric name suffix for a unit.
+// The English name of the unit is used for units without a common suffix.
+func unitSuffix(u senml.Unit) string {
+	if u == senml.None {
+		return ""
+	}
+	if s, ok := unitSuffixes[u]; ok {
+		return s
+	}
+
+	return strings.Trim(sanitize(strings.ToLower(u.Name(senml.English))), "_")
+}
@@ -0,0 +1,111 @@
+package prometheus
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/silkeh/senml"
+)
+
+var testTime = time.Unix(1555487588, 500000000)
+
+var testMeasurements = []senml.Measurement{
+	senml.NewValue("urn:dev:ow:10e2073a01080063:temperature", 23.5, senml.Celsius, testTime, 0),
+	senml.NewValue("urn:dev:ow:10e2073a01080063:humidity", 33.7, senml.RelativeHumidityPercent, testTime, 0),
+	senml.NewSum("urn:dev:ow:10e2073a01080063:energy", 1234, senml.KilowattHour, testTime, 0),
+	senml.NewBoolean("urn:dev:ow:10e2073a01080063:door", true, senml.None, testTime, 0),
+	senml.NewString("urn:dev:ow:10e2073a01080063:label", "kitchen", senml.None, testTime, 0),
+	senml.NewValue("urn:dev:ow:10e2073a01080064:temperature", 21, senml.Celsius, testTime, 0),
+	senml.NewValue("urn:dev:ow:10e2073a01080063:temperature", 22.5, senml.Celsius, testTime.Add(-time.Minute), 0),
+}
+
+func TestEncode(t *testing.T) {
+	tests := map[string]struct {
+		List    []senml.Measurement
+		Format  Format
+		Options Options
+		Output  string
+	}{
+		"text": {
+			List: testMeasurements,
+			Output: `# TYPE door gauge
+door{device="urn:dev:ow:10e2073a01080063"} 1
+# TYPE energy_kilowatt_hours_total counter
+energy_kilowatt_hours_total{device="urn:dev:ow:10e2073a01080063"} 1234
+# TYPE humidity_relative_humidity_percent gauge
+humidity_relative_humidity_percent{device="urn:dev:ow:10e2073a01080063"} 33.7
+# TYPE temperature_celsius gauge
+temperature_celsius{device="urn:dev:ow:10e2073a01080063"} 23.5
+temperature_celsius{device="urn:dev:ow:10e2073a01080064"} 21
+`,
+		},
+		"openmetrics": {
+			List:    testMeasurements[:3],
+			Format:  FormatOpenMetrics,
+			Options: Options{Namespace: "senml", Timestamps: true},
+			Output: `# TYPE senml_energy_kilowatt_hours counter
+# UNIT senml_energy_kilowatt_hours kilowatt_hours
+senml_energy_kilowatt_hours_total{device="urn:dev:ow:10e2073a01080063"} 1234 1555487588.5
+# TYPE senml_humidity_relative_humidity_percent gauge
+# UNIT senml_humidity_relative_humidity_percent relative_humidity_percent
+senml_humidity_relative_humidity_percent{device="urn:dev:ow:10e2073a01080063"} 33.7 1555487588.5
+# TYPE senml_temperature_celsius gauge
+# UNIT senml_temperature_celsius celsius
+senml_temperature_celsius{device="urn:dev:ow:10e2073a01080063"} 23.5 1555487588.5
+# EOF
+`,
+		},
+		"labels": {
+			List: []senml.Measurement{
+				senml.NewValue("building-1/room.2/sensor/temperature", 20, senml.Celsius, testTime, 0),
+				senml.NewValue("building-1/temperature", 19, senml.Celsius, testTime, 0),
+				senml.NewSum("requests_total", 5, senml.None, testTime, 0),
+			},
+			Options: Options{Labels: []string{"building", "room"}, Timestamps: true},
+			Output: `# TYPE requests_total counter
+requests_total 5 1555487588500
+# TYPE sensor_temperature_celsius gauge
+sensor_temperature_celsius{building="building-1",room="room.2"} 20 1555487588500
+# TYPE temperature_celsius gauge
+temperature_celsius{building="building-1"} 19 1555487588500
+`,
+		},
+		"special values": {
+			List: []senml.Measurement{
+				senml.NewValue("a\"b\\c:1-value", math.NaN(), senml.None, time.Time{}, 0),
+				senml.NewValue("inf", math.Inf(1), senml.Unit("m/s2"), time.Time{}, 0),
+				senml.NewValue("ninf", math.Inf(-1), senml.Unit("lm"), time.Time{}, 0),
+			},
+			Options: Options{Timestamps: true},
+			Output: `# TYPE _1_value gauge
+_1_value{device="a\"b\\c"} NaN
+# TYPE inf_meter_per_square_second gauge
+inf_meter_per_square_second +Inf
+# TYPE ninf_lumen gauge
+ninf_lumen -Inf
+`,
+		},
+	}
+
+	for n, test := range tests {
+		b, err := Encode(test.List, test.Format, test.Options)
+		if err != nil {
+			t.Errorf("Error encoding %s: %s", n, err)
+			continue
+		}
+		if string(b) != test.Output {
+			t.Errorf("Output of %s incorrect, got:\n%s\nexpected:\n%s", n, b, test.Output)
+		}
+	}
+}
+
+func TestEncodeConflict(t *testing.T) {
+	list := []senml.Measurement{
+		senml.NewValue("a:energy", 1, senml.None, testTime, 0),
+		senml.NewSum("b:energy", 2, senml.None, testTime, 0),
+	}
+	if _, err := Encode(list, FormatText, Options{}); err == nil {
+		t.Errorf("Expected error for conflicting metric types")
+	}
+}
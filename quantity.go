@@ -0,0 +1,243 @@
+package senml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dimension represents the dimension of a unit as the exponents of the SI
+// base quantities: length, mass, time, electric current, temperature,
+// amount of substance and luminous intensity.
+type Dimension [7]int8
+
+// dimensionSymbols contains the symbols of the SI base units of each dimension.
+var dimensionSymbols = [7]string{"m", "kg", "s", "A", "K", "mol", "cd"}
+
+// Mul returns the dimension of the product of two dimensions.
+func (d Dimension) Mul(o Dimension) (r Dimension) {
+	for i := range d {
+		r[i] = d[i] + o[i]
+	}
+	return
+}
+
+// Div returns the dimension of the quotient of two dimensions.
+func (d Dimension) Div(o Dimension) (r Dimension) {
+	for i := range d {
+		r[i] = d[i] - o[i]
+	}
+	return
+}
+
+// String returns the dimension in SI base units, eg: "m2.kg.s-3".
+func (d Dimension) String() string {
+	var strs []string
+	for i, e := range d {
+		switch e {
+		case 0:
+		case 1:
+			strs = append(strs, dimensionSymbols[i])
+		default:
+			strs = append(strs, dimensionSymbols[i]+strconv.Itoa(int(e)))
+		}
+	}
+	if len(strs) == 0 {
+		return "1"
+	}
+	return strings.Join(strs, ".")
+}
+
+// unitDimensions contains the dimensions of the primary units.
+// Units that are not listed are dimensionless.
+var unitDimensions = map[Unit]Dimension{
+	Meter:                    {1, 0, 0, 0, 0, 0, 0},
+	Kilogram:                 {0, 1, 0, 0, 0, 0, 0},
+	Gram:                     {0, 1, 0, 0, 0, 0, 0},
+	Second:                   {0, 0, 1, 0, 0, 0, 0},
+	Ampere:                   {0, 0, 0, 1, 0, 0, 0},
+	Kelvin:                   {0, 0, 0, 0, 1, 0, 0},
+	Candela:                  {0, 0, 0, 0, 0, 0, 1},
+	Mole:                     {0, 0, 0, 0, 0, 1, 0},
+	Hertz:                    {0, 0, -1, 0, 0, 0, 0},
+	Newton:                   {1, 1, -2, 0, 0, 0, 0},
+	Pascal:                   {-1, 1, -2, 0, 0, 0, 0},
+	Joule:                    {2, 1, -2, 0, 0, 0, 0},
+	Watt:                     {2, 1, -3, 0, 0, 0, 0},
+	Coulomb:                  {0, 0, 1, 1, 0, 0, 0},
+	Volt:                     {2, 1, -3, -1, 0, 0, 0},
+	Farad:                    {-2, -1, 4, 2, 0, 0, 0},
+	Ohm:                      {2, 1, -3, -2, 0, 0, 0},
+	Siemens:                  {-2, -1, 3, 2, 0, 0, 0},
+	Weber:                    {2, 1, -2, -1, 0, 0, 0},
+	Tesla:                    {0, 1, -2, -1, 0, 0, 0},
+	Henry:                    {2, 1, -2, -2, 0, 0, 0},
+	Celsius:                  {0, 0, 0, 0, 1, 0, 0},
+	Lumen:                    {0, 0, 0, 0, 0, 0, 1},
+	Lux:                      {-2, 0, 0, 0, 0, 0, 1},
+	Becquerel:                {0, 0, -1, 0, 0, 0, 0},
+	Gray:                     {2, 0, -2, 0, 0, 0, 0},
+	Sievert:                  {2, 0, -2, 0, 0, 0, 0},
+	Katal:                    {0, 0, -1, 0, 0, 1, 0},
+	SquareMeter:              {2, 0, 0, 0, 0, 0, 0},
+	CubicMeter:               {3, 0, 0, 0, 0, 0, 0},
+	Liter:                    {3, 0, 0, 0, 0, 0, 0},
+	MeterPerSecond:           {1, 0, -1, 0, 0, 0, 0},
+	MeterPerSquareSecond:     {1, 0, -2, 0, 0, 0, 0},
+	CubicMeterPerSecond:      {3, 0, -1, 0, 0, 0, 0},
+	LiterPerSecond:           {3, 0, -1, 0, 0, 0, 0},
+	WattPerSquareMeter:       {0, 1, -3, 0, 0, 0, 0},
+	CandelaPerSquareMeter:    {-2, 0, 0, 0, 0, 0, 1},
+	BitPerSecond:             {0, 0, -1, 0, 0, 0, 0},
+	RemainingBatterySeconds:  {0, 0, 1, 0, 0, 0, 0},
+	Rate:                     {0, 0, -1, 0, 0, 0, 0},
+	RPM:                      {0, 0, -1, 0, 0, 0, 0},
+	HeartRate:                {0, 0, -1, 0, 0, 0, 0},
+	Conductivity:             {-3, -1, 3, 2, 0, 0, 0},
+	VoltAmpere:               {2, 1, -3, 0, 0, 0, 0},
+	VoltAmpereSecond:         {2, 1, -2, 0, 0, 0, 0},
+	VoltAmpereReactive:       {2, 1, -3, 0, 0, 0, 0},
+	VoltAmpereReactiveSecond: {2, 1, -2, 0, 0, 0, 0},
+	JoulePerMeter:            {1, 1, -2, 0, 0, 0, 0},
+	KilogramPerCubicMeter:    {-3, 1, 0, 0, 0, 0, 0},
+}
+
+// derivedUnits contains the preferred units for results of multiplication
+// and division, in order of preference.
+var derivedUnits = []Unit{
+	Ratio, Meter, Kilogram, Second, Ampere, Kelvin, Candela, Mole,
+	Hertz, Newton, Pascal, Joule, Watt, Coulomb, Volt, Farad, Ohm, Siemens,
+	Weber, Tesla, Henry, Lux, Gray, Katal, SquareMeter, CubicMeter,
+	MeterPerSecond, MeterPerSquareSecond, CubicMeterPerSecond,
+	WattPerSquareMeter, CandelaPerSquareMeter, Conductivity,
+	JoulePerMeter, KilogramPerCubicMeter,
+}
+
+// derivedUnit returns the unit for a dimension resulting from multiplication or division.
+func derivedUnit(d Dimension) (Unit, error) {
+	for _, u := range derivedUnits {
+		if u.Info().Dimension == d {
+			return u, nil
+		}
+	}
+	return None, fmt.Errorf("no unit with dimension %s", d)
+}
+
+// Quantity represents a value with a unit.
+type Quantity struct {
+	Value float64
+	Unit  Unit
+}
+
+// NewQuantity returns a new Quantity with the given value and unit.
+func NewQuantity(value float64, unit Unit) Quantity {
+	return Quantity{Value: value, Unit: unit}
+}
+
+// Dimension returns the dimension of the quantity.
+func (q Quantity) Dimension() Dimension {
+	return q.Unit.Info().Dimension
+}
+
+// String returns the quantity as a string, eg: "23.5 Cel".
+func (q Quantity) String() string {
+	if q.Unit == None {
+		return strconv.FormatFloat(q.Value, 'g', -1, 64)
+	}
+	return strconv.FormatFloat(q.Value, 'g', -1, 64) + " " + string(q.Unit)
+}
+
+// Convert returns the quantity converted to the given unit.
+func (q Quantity) Convert(to Unit) (Quantity, error) {
+	v, err := q.Unit.Convert(q.Value, to)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Value: v, Unit: to}, nil
+}
+
+// Add returns the sum of two quantities in the unit of the first.
+// The second quantity is treated as a difference, so the offset of its unit
+// is not applied, eg: 20 Cel plus 1 K results in 21 Cel.
+// An error is returned if the units are incompatible.
+func (q Quantity) Add(o Quantity) (Quantity, error) {
+	v, err := o.Unit.convertDifference(o.Value, q.Unit)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Value: q.Value + v, Unit: q.Unit}, nil
+}
+
+// Sub returns the difference of two quantities in the unit of the first.
+// The second quantity is treated as a difference, as in Add.
+// An error is returned if the units are incompatible.
+func (q Quantity) Sub(o Quantity) (Quantity, error) {
+	v, err := o.Unit.convertDifference(o.Value, q.Unit)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Value: q.Value - v, Unit: q.Unit}, nil
+}
+
+// Mul returns the product of two quantities in a derived unit,
+// eg: W multiplied by s results in J.
+// An error is returned if either unit is unknown or logarithmic,
+// or if no unit exists for the resulting dimension.
+func (q Quantity) Mul(o Quantity) (Quantity, error) {
+	a, b, err := coherentQuantities(q, o)
+	if err != nil {
+		return Quantity{}, err
+	}
+
+	u, err := derivedUnit(a.Dimension().Mul(b.Dimension()))
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Value: a.Value * b.Value, Unit: u}, nil
+}
+
+// Div returns the quotient of two quantities in a derived unit,
+// eg: J divided by s results in W.
+// An error is returned if either unit is unknown or logarithmic,
+// or if no unit exists for the resulting dimension.
+func (q Quantity) Div(o Quantity) (Quantity, error) {
+	a, b, err := coherentQuantities(q, o)
+	if err != nil {
+		return Quantity{}, err
+	}
+
+	u, err := derivedUnit(a.Dimension().Div(b.Dimension()))
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Value: a.Value / b.Value, Unit: u}, nil
+}
+
+// coherentQuantities converts two quantities to their reference units.
+func coherentQuantities(a, b Quantity) (Quantity, Quantity, error) {
+	var err error
+	for _, q := range []*Quantity{&a, &b} {
+		if !q.Unit.Known() {
+			return a, b, fmt.Errorf("unknown unit %q", q.Unit)
+		}
+		if q.Unit.Info().Logarithmic {
+			return a, b, fmt.Errorf("unsupported logarithmic unit %q", q.Unit)
+		}
+		*q, err = q.Convert(q.Unit.reference().Unit)
+		if err != nil {
+			return a, b, err
+		}
+	}
+	return a, b, nil
+}
+
+// ToValue returns a Value measurement with the quantity and the given attributes.
+func (q Quantity) ToValue(name string, time time.Time, updateTime time.Duration) *Value {
+	return NewValue(name, q.Value, q.Unit, time, updateTime)
+}
+
+// Quantity returns the value and unit of the measurement as a Quantity.
+func (v *Value) Quantity() Quantity {
+	return Quantity{Value: v.Value, Unit: v.Unit}
+}
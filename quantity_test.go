@@ -0,0 +1,91 @@
+package senml
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestQuantityArithmetic(t *testing.T) {
+	tests := map[string]struct {
+		Op     func(a, b Quantity) (Quantity, error)
+		A, B   Quantity
+		Result Quantity
+	}{
+		"Add":          {Quantity.Add, NewQuantity(1, Kilowatt), NewQuantity(500, Watt), NewQuantity(1.5, Kilowatt)},
+		"Add Celsius":  {Quantity.Add, NewQuantity(20, Celsius), NewQuantity(1.5, Celsius), NewQuantity(21.5, Celsius)},
+		"Add Kelvin":   {Quantity.Add, NewQuantity(20, Celsius), NewQuantity(1, Kelvin), NewQuantity(21, Celsius)},
+		"Sub Celsius":  {Quantity.Sub, NewQuantity(300, Kelvin), NewQuantity(2, Celsius), NewQuantity(298, Kelvin)},
+		"Sub":          {Quantity.Sub, NewQuantity(1, KilowattHour), NewQuantity(500, WattHour), NewQuantity(0.5, KilowattHour)},
+		"Mul power":    {Quantity.Mul, NewQuantity(230, Volt), NewQuantity(2, Ampere), NewQuantity(460, Watt)},
+		"Mul energy":   {Quantity.Mul, NewQuantity(2, Kilowatt), NewQuantity(1, Hour), NewQuantity(7200000, Joule)},
+		"Mul ratio":    {Quantity.Mul, NewQuantity(50, Percent), NewQuantity(100, Watt), NewQuantity(50, Watt)},
+		"Div power":    {Quantity.Div, NewQuantity(3600, Joule), NewQuantity(1, Minute), NewQuantity(60, Watt)},
+		"Div speed":    {Quantity.Div, NewQuantity(36, Kilometer), NewQuantity(1, Hour), NewQuantity(10, MeterPerSecond)},
+		"Div resistor": {Quantity.Div, NewQuantity(5, Volt), NewQuantity(10, Milliampere), NewQuantity(500, Ohm)},
+		"Div ratio":    {Quantity.Div, NewQuantity(1, Kilowatt), NewQuantity(4000, Watt), NewQuantity(0.25, Ratio)},
+	}
+
+	for n, test := range tests {
+		res, err := test.Op(test.A, test.B)
+		if err != nil {
+			t.Errorf("Error in %s: %s", n, err)
+			continue
+		}
+		if res.Unit != test.Result.Unit || math.Abs(res.Value-test.Result.Value) > 1e-9*math.Abs(test.Result.Value) {
+			t.Errorf("Result of %s incorrect, got %s, expected %s", n, res, test.Result)
+		}
+	}
+}
+
+func TestQuantityErrors(t *testing.T) {
+	tests := map[string]struct {
+		Op   func(a, b Quantity) (Quantity, error)
+		A, B Quantity
+	}{
+		"Add incompatible":    {Quantity.Add, NewQuantity(1, Watt), NewQuantity(1, Joule)},
+		"Add dimensionless":   {Quantity.Add, NewQuantity(1, Count), NewQuantity(1, Radian)},
+		"Sub incompatible":    {Quantity.Sub, NewQuantity(1, Meter), NewQuantity(1, Second)},
+		"Mul logarithmic":     {Quantity.Mul, NewQuantity(1, DecibelMilliwatt), NewQuantity(1, Second)},
+		"Mul unknown":         {Quantity.Mul, NewQuantity(1, "foo"), NewQuantity(1, Second)},
+		"Mul unknown derived": {Quantity.Mul, NewQuantity(1, Meter), NewQuantity(1, Ampere)},
+		"Div logarithmic":     {Quantity.Div, NewQuantity(1, Second), NewQuantity(1, Decibel)},
+	}
+
+	for n, test := range tests {
+		if res, err := test.Op(test.A, test.B); err == nil {
+			t.Errorf("Expected error for %s, got %s", n, res)
+		}
+	}
+}
+
+func TestQuantityValue(t *testing.T) {
+	now := time.Now()
+	v := NewValue("power", 12.5, Watt, now, time.Minute)
+
+	q := v.Quantity()
+	if q != NewQuantity(12.5, Watt) {
+		t.Errorf("Quantity incorrect, got %s", q)
+	}
+
+	if r := q.ToValue("power", now, time.Minute); !r.Equal(v) {
+		t.Errorf("Value incorrect, got:\n%#v\nexpected:\n%#v", r, v)
+	}
+}
+
+func TestDimensionString(t *testing.T) {
+	tests := map[Unit]string{
+		Watt:   "m2.kg.s-3",
+		Meter:  "m",
+		Ratio:  "1",
+		Farad:  "m-2.kg-1.s4.A2",
+		Lux:    "m-2.cd",
+		Kelvin: "K",
+	}
+
+	for u, exp := range tests {
+		if s := u.Info().Dimension.String(); s != exp {
+			t.Errorf("Dimension of %q incorrect, got %q, expected %q", u, s, exp)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package senml
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// rateKinds contains the kind of quantity of the rate of change
+// of cumulative kinds of quantities.
+var rateKinds = map[string]string{
+	"count":               "event rate",
+	"length":              "velocity",
+	"volume":              "flow rate",
+	"energy":              "power",
+	"apparent energy":     "apparent power",
+	"reactive energy":     "reactive power",
+	"electric charge":     "electric current",
+	"information content": "data rate",
+}
+
+// rateUnit returns the unit of the rate of change of a cumulative unit,
+// and the scale to convert a change per second to that unit.
+// The unit is derived from the kind of quantity, primary unit and scale of the unit,
+// preferring a unit with the same scale per second or per hour, eg: kWh results in kW.
+// The primary unit of the rate is used if no such unit is known.
+func rateUnit(u Unit) (Unit, float64, error) {
+	if u == None {
+		return Rate, 1, nil
+	}
+
+	ref := u.reference()
+	info := ref.Unit.Info()
+	kind, ok := rateKinds[info.Kind]
+	if !ok || ref.Offset != 0 {
+		return None, 0, fmt.Errorf("no rate unit for unit %q", u)
+	}
+
+	dim := info.Dimension.Div(unitDimensions[Second])
+	var primary Unit
+	var candidates []conversion
+	for _, c := range Units() {
+		ci := c.Info()
+		if ci.Kind != kind || (ci.Deprecated && !u.Info().Deprecated) {
+			continue
+		}
+		if cr := c.reference(); cr.Offset == 0 && cr.Unit.Info().Dimension == dim {
+			if cr.Unit == c && primary == None {
+				primary = c
+			}
+			candidates = append(candidates, conversion{Unit: c, Scale: cr.Scale})
+		}
+	}
+	if primary == None {
+		return None, 0, fmt.Errorf("no rate unit for unit %q", u)
+	}
+
+	for _, scale := range []float64{ref.Scale, ref.Scale / 3600} {
+		for _, c := range candidates {
+			if c.Unit.reference().Unit == primary && math.Abs(c.Scale-scale) <= 1e-9*scale {
+				return c.Unit, ref.Scale / c.Scale, nil
+			}
+		}
+	}
+	return primary, ref.Scale, nil
+}
+
+// DeriveRate converts a time-ordered list of Sum measurements with a single name
+// into Value measurements containing the rate of change per unit of time.
+// The unit of the result is derived from the unit of the sum,
+// eg: Wh results in W, and count results in 1/s.
+//
+// A decrease of the sum is treated as a counter reset, in which case the
+// counter is assumed to have restarted from zero.
+// If wrap is non-zero, a decrease is treated as a wrap-around of a counter with
+// that maximum value if the resulting increase is less than half of wrap.
+func DeriveRate(list []Measurement, wrap float64) ([]Measurement, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	// Validate and sort the input
+	sums := make([]*Sum, len(list))
+	for i, m := range list {
+		s, ok := m.(*Sum)
+		if !ok {
+			return nil, fmt.Errorf("unsupported measurement type for rate derivation: %T", m)
+		}
+		if s.Name != list[0].Attrs().Name {
+			return nil, fmt.Errorf("multiple names in rate input: %q and %q", list[0].Attrs().Name, s.Name)
+		}
+		if s.Unit != list[0].Attrs().Unit {
+			return nil, fmt.Errorf("multiple units in rate input: %q and %q", list[0].Attrs().Unit, s.Unit)
+		}
+		sums[i] = s
+	}
+	sort.SliceStable(sums, func(i, j int) bool {
+		return sums[i].Time.Before(sums[j].Time)
+	})
+
+	unit, scale, err := rateUnit(sums[0].Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Measurement, 0, len(sums)-1)
+	for i := 1; i < len(sums); i++ {
+		a, b := sums[i-1], sums[i]
+		d := b.Time.Sub(a.Time).Seconds()
+		if d == 0 {
+			return nil, fmt.Errorf("duplicate time in rate input: %s", b.Time)
+		}
+
+		delta := b.Value - a.Value
+		if delta < 0 {
+			if wrap > 0 && wrap-a.Value+b.Value < wrap/2 {
+				delta = wrap - a.Value + b.Value
+			} else {
+				delta = b.Value
+			}
+		}
+
+		res = append(res, NewValue(b.Name, delta/d*scale, unit, b.Time, b.UpdateTime))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,134 @@
+package senml
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeriveRate(t *testing.T) {
+	t0 := time.Unix(1600000000, 0)
+	tests := map[string]struct {
+		Wrap   float64
+		Input  []Measurement
+		Result []Measurement
+	}{
+		"Energy": {
+			Input: []Measurement{
+				NewSum("energy", 1000, WattHour, t0, 0),
+				NewSum("energy", 1001, WattHour, t0.Add(time.Minute), 0),
+				NewSum("energy", 1003, WattHour, t0.Add(2*time.Minute), 0),
+			},
+			Result: []Measurement{
+				NewValue("energy", 60, Watt, t0.Add(time.Minute), 0),
+				NewValue("energy", 120, Watt, t0.Add(2*time.Minute), 0),
+			},
+		},
+		"Kilowatt-hour": {
+			Input: []Measurement{
+				NewSum("energy", 1, KilowattHour, t0, 0),
+				NewSum("energy", 2, KilowattHour, t0.Add(time.Hour), 0),
+			},
+			Result: []Measurement{
+				NewValue("energy", 1, Kilowatt, t0.Add(time.Hour), 0),
+			},
+		},
+		"Bytes": {
+			Input: []Measurement{
+				NewSum("received", 0, Byte, t0, 0),
+				NewSum("received", 100, Byte, t0.Add(10*time.Second), 0),
+			},
+			Result: []Measurement{
+				NewValue("received", 10, BytePerSecond, t0.Add(10*time.Second), 0),
+			},
+		},
+		"Kibibytes": {
+			Input: []Measurement{
+				NewSum("received", 0, Kibibyte, t0, 0),
+				NewSum("received", 1, Kibibyte, t0.Add(time.Second), 0),
+			},
+			Result: []Measurement{
+				NewValue("received", 8192, BitPerSecond, t0.Add(time.Second), 0),
+			},
+		},
+		"Unordered count": {
+			Input: []Measurement{
+				NewSum("packets", 20, Count, t0.Add(10*time.Second), 0),
+				NewSum("packets", 10, Count, t0, 0),
+			},
+			Result: []Measurement{
+				NewValue("packets", 1, Rate, t0.Add(10*time.Second), 0),
+			},
+		},
+		"Reset": {
+			Input: []Measurement{
+				NewSum("packets", 100, Count, t0, 0),
+				NewSum("packets", 10, Count, t0.Add(10*time.Second), 0),
+			},
+			Result: []Measurement{
+				NewValue("packets", 1, Rate, t0.Add(10*time.Second), 0),
+			},
+		},
+		"Wrap": {
+			Wrap: 256,
+			Input: []Measurement{
+				NewSum("packets", 250, Count, t0, 0),
+				NewSum("packets", 4, Count, t0.Add(10*time.Second), 0),
+			},
+			Result: []Measurement{
+				NewValue("packets", 1, Rate, t0.Add(10*time.Second), 0),
+			},
+		},
+		"Reset with wrap": {
+			Wrap: 256,
+			Input: []Measurement{
+				NewSum("packets", 100, Count, t0, 0),
+				NewSum("packets", 10, Count, t0.Add(10*time.Second), 0),
+			},
+			Result: []Measurement{
+				NewValue("packets", 1, Rate, t0.Add(10*time.Second), 0),
+			},
+		},
+	}
+
+	for n, test := range tests {
+		res, err := DeriveRate(test.Input, test.Wrap)
+		if err != nil {
+			t.Errorf("Error deriving rate for %s: %s", n, err)
+			continue
+		}
+
+		if len(res) != len(test.Result) || !equal(res, test.Result) {
+			t.Errorf("Rate for %s incorrect, got:\n%s\nexpected:\n%s", n, toString(res), toString(test.Result))
+		}
+	}
+}
+
+func TestDeriveRateErrors(t *testing.T) {
+	now := time.Now()
+	tests := map[string][]Measurement{
+		"Multiple names": {
+			NewSum("a", 1, Count, now, 0),
+			NewSum("b", 1, Count, now.Add(time.Second), 0),
+		},
+		"Multiple units": {
+			NewSum("a", 1, Count, now, 0),
+			NewSum("a", 1, WattHour, now.Add(time.Second), 0),
+		},
+		"Invalid type": {
+			NewValue("a", 1, Count, now, 0),
+		},
+		"Unknown unit": {
+			NewSum("a", 1, Celsius, now, 0),
+		},
+		"Duplicate time": {
+			NewSum("a", 1, Count, now, 0),
+			NewSum("a", 2, Count, now, 0),
+		},
+	}
+
+	for n, list := range tests {
+		if _, err := DeriveRate(list, 0); err == nil {
+			t.Errorf("Expected error for %s", n)
+		}
+	}
+}
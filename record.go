@@ -25,4 +25,11 @@ type Record struct {
 	Sum          Numeric  `json:"s,omitempty" xml:"s,attr,omitempty" codec:"5"`
 	Time         Numeric  `json:"t,omitempty" xml:"t,attr,omitempty" codec:"6"`
 	UpdateTime   Numeric  `json:"ut,omitempty" xml:"ut,attr,omitempty" codec:"7"`
+
+	// ObjectLink is the OMA LwM2M object link value, eg: "3303:0".
+	ObjectLink string `json:"vlo,omitempty" xml:"vlo,attr,omitempty" codec:"-"`
+
+	// Remove marks a record of an iPATCH pack that removes the matching records.
+	// It is encoded as a null value in the SenML etch formats, see ApplyPatch.
+	Remove bool `json:"-" xml:"-" codec:"-"`
 }
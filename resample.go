@@ -0,0 +1,139 @@
+package senml
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Interpolation represents the method used to determine a value between measurements.
+type Interpolation int
+
+// Supported interpolation methods.
+const (
+	// Linear interpolates linearly between the surrounding measurements.
+	Linear Interpolation = iota
+
+	// Previous uses the value of the last measurement at or before the requested time.
+	Previous
+
+	// Nearest uses the value of the measurement closest to the requested time.
+	Nearest
+)
+
+// GapPolicy represents the way gaps in a measurement series are handled.
+// A gap exists when the time between two measurements exceeds the UpdateTime of the first.
+type GapPolicy int
+
+// Supported gap policies.
+const (
+	// SkipGaps omits any resampled value that falls within a gap.
+	SkipGaps GapPolicy = iota
+
+	// NaNGaps sets any resampled value that falls within a gap to NaN.
+	NaNGaps
+
+	// IgnoreGaps interpolates over gaps as if UpdateTime was not set.
+	IgnoreGaps
+)
+
+// Resample resamples a list of Value measurements with a single name to a
+// regular grid with the given step.
+// The grid is aligned to multiples of step since the Unix epoch,
+// and spans from the first up to and including the last measurement.
+// The UpdateTime of a measurement is used as the maximum interpolation span,
+// any resampled value beyond that span is handled according to the gap policy.
+func Resample(list []Measurement, step time.Duration, method Interpolation, gaps GapPolicy) ([]Measurement, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("invalid resample step: %s", step)
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	// Validate and sort the input
+	values := make([]*Value, len(list))
+	for i, m := range list {
+		v, ok := m.(*Value)
+		if !ok {
+			return nil, fmt.Errorf("unsupported measurement type for resampling: %T", m)
+		}
+		if v.Name != list[0].Attrs().Name {
+			return nil, fmt.Errorf("multiple names in resample input: %q and %q", list[0].Attrs().Name, v.Name)
+		}
+		values[i] = v
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].Time.Before(values[j].Time)
+	})
+
+	first, last := values[0], values[len(values)-1]
+	start := alignTime(first.Time, step)
+
+	var res []Measurement
+	i := 0
+	for t := start; !t.After(last.Time); t = t.Add(step) {
+		// Find the measurements surrounding t: values[i] <= t <= values[i+1]
+		for i < len(values)-1 && !values[i+1].Time.After(t) {
+			i++
+		}
+		a := values[i]
+		b := a
+		if i < len(values)-1 {
+			b = values[i+1]
+		}
+
+		v, ok := interpolate(a, b, t, method, gaps != IgnoreGaps)
+		if !ok {
+			if gaps == SkipGaps {
+				continue
+			}
+			v = math.NaN()
+		}
+
+		res = append(res, NewValue(first.Name, v, first.Unit, t, 0))
+	}
+
+	return res, nil
+}
+
+// interpolate returns the value at time t, which lies between measurements a and b.
+// The returned boolean is false if t lies within a gap and checkGaps is true.
+func interpolate(a, b *Value, t time.Time, method Interpolation, checkGaps bool) (float64, bool) {
+	span := b.Time.Sub(a.Time)
+	gap := checkGaps && a.UpdateTime > 0 && span > a.UpdateTime
+	held := !checkGaps || a.UpdateTime == 0 || t.Sub(a.Time) <= a.UpdateTime
+
+	switch {
+	case t.Equal(a.Time) || span == 0:
+		return a.Value, true
+	case t.Equal(b.Time):
+		return b.Value, true
+	}
+
+	switch method {
+	case Previous:
+		return a.Value, held
+	case Nearest:
+		if t.Sub(a.Time) <= b.Time.Sub(t) {
+			return a.Value, held
+		}
+		return b.Value, !gap
+	default:
+		f := float64(t.Sub(a.Time)) / float64(span)
+		return a.Value + f*(b.Value-a.Value), !gap
+	}
+}
+
+// alignTime returns the first multiple of step since the Unix epoch at or after t.
+func alignTime(t time.Time, step time.Duration) time.Time {
+	rem := time.Duration(t.UnixNano() % int64(step))
+	if rem < 0 {
+		rem += step
+	}
+	if rem == 0 {
+		return t
+	}
+	return t.Add(step - rem)
+}
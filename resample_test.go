@@ -0,0 +1,100 @@
+package senml
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestResample(t *testing.T) {
+	t0 := time.Unix(1600000000, 0)
+	input := []Measurement{
+		NewValue("temp", 10, Celsius, t0, 0),
+		NewValue("temp", 20, Celsius, t0.Add(10*time.Second), 0),
+		NewValue("temp", 30, Celsius, t0.Add(20*time.Second), 5*time.Second),
+		NewValue("temp", 50, Celsius, t0.Add(40*time.Second), 0),
+	}
+
+	nan := math.NaN()
+	tests := map[string]struct {
+		Method Interpolation
+		Gaps   GapPolicy
+		Result []float64
+	}{
+		"Linear":             {Linear, IgnoreGaps, []float64{10, 15, 20, 25, 30, 35, 40, 45, 50}},
+		"Linear skip gaps":   {Linear, SkipGaps, []float64{10, 15, 20, 25, 30, 50}},
+		"Linear NaN gaps":    {Linear, NaNGaps, []float64{10, 15, 20, 25, 30, nan, nan, nan, 50}},
+		"Previous":           {Previous, IgnoreGaps, []float64{10, 10, 20, 20, 30, 30, 30, 30, 50}},
+		"Previous skip gaps": {Previous, SkipGaps, []float64{10, 10, 20, 20, 30, 30, 50}},
+		"Nearest":            {Nearest, IgnoreGaps, []float64{10, 10, 20, 20, 30, 30, 30, 50, 50}},
+		"Nearest NaN gaps":   {Nearest, NaNGaps, []float64{10, 10, 20, 20, 30, 30, nan, nan, 50}},
+	}
+
+	for n, test := range tests {
+		res, err := Resample(input, 5*time.Second, test.Method, test.Gaps)
+		if err != nil {
+			t.Errorf("Error resampling %s: %s", n, err)
+			continue
+		}
+
+		got := make([]float64, len(res))
+		for i, m := range res {
+			got[i] = m.(*Value).Value
+		}
+		if len(got) != len(test.Result) {
+			t.Errorf("Resample for %s incorrect, got:\n%v\nexpected:\n%v", n, got, test.Result)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.Result[i] && !(math.IsNaN(got[i]) && math.IsNaN(test.Result[i])) {
+				t.Errorf("Resample for %s incorrect, got:\n%v\nexpected:\n%v", n, got, test.Result)
+				break
+			}
+		}
+	}
+}
+
+func TestResampleAlignment(t *testing.T) {
+	t0 := time.Unix(1600000003, 0)
+	input := []Measurement{
+		NewValue("temp", 0, Celsius, t0, 0),
+		NewValue("temp", 12, Celsius, t0.Add(12*time.Second), 0),
+	}
+
+	res, err := Resample(input, 5*time.Second, Linear, SkipGaps)
+	if err != nil {
+		t.Fatalf("Error resampling: %s", err)
+	}
+
+	exp := []Measurement{
+		NewValue("temp", 2, Celsius, time.Unix(1600000005, 0), 0),
+		NewValue("temp", 7, Celsius, time.Unix(1600000010, 0), 0),
+		NewValue("temp", 12, Celsius, time.Unix(1600000015, 0), 0),
+	}
+	if len(res) != len(exp) || !equal(res, exp) {
+		t.Errorf("Resample incorrect, got:\n%s\nexpected:\n%s", toString(res), toString(exp))
+	}
+}
+
+func TestResampleErrors(t *testing.T) {
+	now := time.Now()
+	tests := map[string][]Measurement{
+		"Multiple names": {
+			NewValue("a", 1, None, now, 0),
+			NewValue("b", 1, None, now, 0),
+		},
+		"Invalid type": {
+			NewString("a", "1", None, now, 0),
+		},
+	}
+
+	for n, list := range tests {
+		if _, err := Resample(list, time.Second, Linear, SkipGaps); err == nil {
+			t.Errorf("Expected error for %s", n)
+		}
+	}
+
+	if _, err := Resample(nil, 0, Linear, SkipGaps); err == nil {
+		t.Errorf("Expected error for zero step")
+	}
+}
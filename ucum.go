@@ -0,0 +1,170 @@
+package senml
+
+import "fmt"
+
+// ucumCodes contains the UCUM codes of units that have an exact equivalent.
+var ucumCodes = map[Unit]string{
+	// RFC8428
+	Meter:                 "m",
+	Kilogram:              "kg",
+	Gram:                  "g",
+	Second:                "s",
+	Ampere:                "A",
+	Kelvin:                "K",
+	Candela:               "cd",
+	Mole:                  "mol",
+	Hertz:                 "Hz",
+	Radian:                "rad",
+	Steradian:             "sr",
+	Newton:                "N",
+	Pascal:                "Pa",
+	Joule:                 "J",
+	Watt:                  "W",
+	Coulomb:               "C",
+	Volt:                  "V",
+	Farad:                 "F",
+	Ohm:                   "Ohm",
+	Siemens:               "S",
+	Weber:                 "Wb",
+	Tesla:                 "T",
+	Henry:                 "H",
+	Celsius:               "Cel",
+	Lumen:                 "lm",
+	Lux:                   "lx",
+	Becquerel:             "Bq",
+	Gray:                  "Gy",
+	Sievert:               "Sv",
+	Katal:                 "kat",
+	SquareMeter:           "m2",
+	CubicMeter:            "m3",
+	Liter:                 "l",
+	MeterPerSecond:        "m/s",
+	MeterPerSquareSecond:  "m/s2",
+	CubicMeterPerSecond:   "m3/s",
+	LiterPerSecond:        "l/s",
+	WattPerSquareMeter:    "W/m2",
+	CandelaPerSquareMeter: "cd/m2",
+	Bit:                   "bit",
+	BitPerSecond:          "bit/s",
+	PH:                    "[pH]",
+	Decibel:               "dB",
+	DBW:                   "dB[W]",
+	Bel:                   "B[SPL]",
+	Count:                 "{count}",
+	Ratio:                 "1",
+	Ratio2:                "%",
+	Rate:                  "/s",
+	RPM:                   "/min",
+	HeartRate:             "{beat}/min",
+	HeartBeats:            "{beat}",
+	Conductivity:          "S/m",
+
+	// RFC8798
+	Byte:                  "By",
+	VoltAmpere:            "V.A",
+	VoltAmpereSecond:      "V.A.s",
+	JoulePerMeter:         "J/m",
+	KilogramPerCubicMeter: "kg/m3",
+	Degree:                "deg",
+
+	// Secondary units (RFC8798)
+	Millisecond:            "ms",
+	Minute:                 "min",
+	Hour:                   "h",
+	Megahertz:              "MHz",
+	Kilowatt:               "kW",
+	KilovoltAmpere:         "kV.A",
+	AmpereHour:             "A.h",
+	WattHour:               "W.h",
+	KilowattHour:           "kW.h",
+	KilovoltAmpereHour:     "kV.A.h",
+	WattHourPerKilometer:   "W.h/km",
+	Kibibyte:               "KiBy",
+	Gigabyte:               "GBy",
+	MegabitPerSecond:       "Mbit/s",
+	BytePerSecond:          "By/s",
+	MegabytePerSecond:      "MBy/s",
+	Millivolt:              "mV",
+	Milliampere:            "mA",
+	DecibelMilliwatt:       "dB[mW]",
+	MicrogramPerCubicMeter: "ug/m3",
+	MillimeterPerHour:      "mm/h",
+	MeterPerHour:           "m/h",
+	PartsPerMillion:        "[ppm]",
+	Percent:                "%",
+	Permille:               "[ppth]",
+	Hectopascal:            "hPa",
+	Millimeter:             "mm",
+	Centimeter:             "cm",
+	Kilometer:              "km",
+	KilometerPerHour:       "km/h",
+
+	// Secondary units (CoRE-1)
+	PartsPerBillion:  "[ppb]",
+	PartsPerTrillion: "[pptr]",
+	VoltAmpereHour:   "V.A.h",
+	Milligram:        "mg/l",
+	Microgram:        "ug/l",
+	GramPerLiter:     "g/l",
+}
+
+// ucumUnits contains the units corresponding to UCUM codes.
+// Codes that are not listed here are added from ucumCodes.
+var ucumUnits = map[string]Unit{
+	// Codes for multiple units
+	"%": Percent,
+
+	// Alternative spellings of the liter
+	"L":    Liter,
+	"L/s":  LiterPerSecond,
+	"mg/L": Milligram,
+	"ug/L": Microgram,
+	"g/L":  GramPerLiter,
+}
+
+// init fills the UCUM code to unit map.
+func init() {
+	for u, c := range ucumCodes {
+		if _, ok := ucumUnits[c]; !ok {
+			ucumUnits[c] = u
+		}
+	}
+}
+
+// AcceptUCUM toggles the translation of UCUM codes in Decode.
+// Enabling this option translates any unit that is not known to SenML,
+// but is a UCUM code with an exact SenML equivalent.
+var AcceptUCUM = false
+
+// UCUM returns the UCUM code of the unit.
+// An error is returned if the unit has no exact UCUM equivalent.
+func (u Unit) UCUM() (string, error) {
+	c, ok := ucumCodes[u]
+	if !ok {
+		return "", fmt.Errorf("unit %q has no UCUM equivalent", u)
+	}
+	return c, nil
+}
+
+// FromUCUM returns the unit corresponding to a UCUM code.
+// An error is returned if the code has no exact SenML equivalent.
+func FromUCUM(code string) (Unit, error) {
+	u, ok := ucumUnits[code]
+	if !ok {
+		return None, fmt.Errorf("UCUM code %q has no SenML equivalent", code)
+	}
+	return u, nil
+}
+
+// decodeUnit returns the unit for a unit in a record,
+// translating UCUM codes if enabled.
+func decodeUnit(s string) Unit {
+	u := Unit(s)
+	if !AcceptUCUM || u.Known() {
+		return u
+	}
+	if t, err := FromUCUM(s); err == nil {
+		return t
+	}
+	return u
+}
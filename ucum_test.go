@@ -0,0 +1,85 @@
+package senml
+
+import "testing"
+
+func TestUCUM(t *testing.T) {
+	tests := map[Unit]string{
+		Celsius:      "Cel",
+		KilowattHour: "kW.h",
+		Percent:      "%",
+		Byte:         "By",
+		HeartRate:    "{beat}/min",
+	}
+
+	for u, exp := range tests {
+		c, err := u.UCUM()
+		if err != nil {
+			t.Errorf("Error converting %q to UCUM: %s", u, err)
+			continue
+		}
+		if c != exp {
+			t.Errorf("UCUM code for %q incorrect, got %q, expected %q", u, c, exp)
+		}
+
+		r, err := FromUCUM(c)
+		if err != nil {
+			t.Errorf("Error converting %q from UCUM: %s", c, err)
+			continue
+		}
+		if r != u {
+			t.Errorf("Unit for UCUM code %q incorrect, got %q, expected %q", c, r, u)
+		}
+	}
+}
+
+func TestUCUMRoundTrip(t *testing.T) {
+	for u := range ucumCodes {
+		c, _ := u.UCUM()
+		r, err := FromUCUM(c)
+		if err != nil {
+			t.Errorf("Error converting %q from UCUM: %s", c, err)
+			continue
+		}
+		if r != u && !u.Info().Deprecated {
+			t.Errorf("Unit for UCUM code %q incorrect, got %q, expected %q", c, r, u)
+		}
+	}
+}
+
+func TestUCUMErrors(t *testing.T) {
+	for _, u := range []Unit{Latitude, RelativeHumidityPercent, Kilovar, "foo"} {
+		if c, err := u.UCUM(); err == nil {
+			t.Errorf("Expected error for %q, got %q", u, c)
+		}
+	}
+
+	for _, c := range []string{"[degF]", "cel", "kWh"} {
+		if u, err := FromUCUM(c); err == nil {
+			t.Errorf("Expected error for %q, got %q", c, u)
+		}
+	}
+}
+
+func TestDecodeUCUM(t *testing.T) {
+	defer func() { AcceptUCUM = false }()
+
+	records := []Record{
+		{BaseUnit: "kW.h", Name: "a", Sum: 1},
+		{Name: "b", Unit: "Cel", Value: 2},
+		{Name: "c", Unit: "[ppm]", Value: 3},
+		{Name: "d", Unit: "[degF]", Value: 4},
+	}
+
+	AcceptUCUM = true
+	list, err := Decode(records)
+	if err != nil {
+		t.Fatalf("Error decoding: %s", err)
+	}
+
+	exp := []Unit{KilowattHour, Celsius, PartsPerMillion, "[degF]"}
+	for i, m := range list {
+		if m.Attrs().Unit != exp[i] {
+			t.Errorf("Unit of record %v incorrect, got %q, expected %q", i, m.Attrs().Unit, exp[i])
+		}
+	}
+}
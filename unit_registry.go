@@ -0,0 +1,301 @@
+package senml
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Specifications defining units.
+const (
+	sourceRFC8428 = "RFC8428"
+	sourceRFC8798 = "RFC8798"
+	sourceISO7027 = "ISO 7027-1:2016"
+	sourceCoRE1   = "CoRE-1"
+)
+
+// UnitInfo contains the metadata of a unit.
+type UnitInfo struct {
+	// Symbol is the symbol of the unit as used in SenML.
+	Symbol Unit
+
+	// Description is a human readable description of the unit.
+	Description string
+
+	// Kind is the kind of quantity the unit measures, eg: "temperature".
+	Kind string
+
+	// Source is the specification that registered the unit, eg: "RFC8428".
+	Source string
+
+	// Deprecated is true for units that are marked as not recommended.
+	Deprecated bool
+
+	// Primary is the primary SenML unit of the unit.
+	// This is equal to Symbol for primary units.
+	Primary Unit
+
+	// Scale and Offset describe the conversion to the primary unit:
+	// primary = value*Scale + Offset.
+	Scale  float64
+	Offset float64
+
+	// Dimension is the dimension of the unit in SI base quantities.
+	Dimension Dimension
+
+	// Logarithmic is true for units on a logarithmic scale, eg: dB.
+	Logarithmic bool
+}
+
+// Secondary returns true if the unit is a secondary unit.
+func (i UnitInfo) Secondary() bool {
+	return i.Primary != i.Symbol
+}
+
+// primaryUnit returns the UnitInfo for a primary unit.
+func primaryUnit(u Unit, desc, kind, source string) UnitInfo {
+	return UnitInfo{Symbol: u, Description: desc, Kind: kind, Source: source, Primary: u, Scale: 1}
+}
+
+// secondaryUnit returns the UnitInfo for a secondary unit.
+func secondaryUnit(u Unit, desc string, primary Unit, scale, offset float64, source string) UnitInfo {
+	return UnitInfo{Symbol: u, Description: desc, Source: source, Primary: primary, Scale: scale, Offset: offset}
+}
+
+// deprecated returns the UnitInfo marked as not recommended.
+func (i UnitInfo) deprecated() UnitInfo {
+	i.Deprecated = true
+	return i
+}
+
+// logarithmic returns the UnitInfo marked as logarithmic.
+func (i UnitInfo) logarithmic() UnitInfo {
+	i.Logarithmic = true
+	return i
+}
+
+// unitRegistry contains the metadata of all known units.
+var (
+	unitRegistry      = make(map[Unit]UnitInfo)
+	unitRegistryMutex sync.RWMutex
+)
+
+// init fills the unit registry with the units defined in the specifications.
+func init() {
+	for _, i := range []UnitInfo{
+		// RFC8428
+		primaryUnit(Meter, "meter", "length", sourceRFC8428),
+		primaryUnit(Kilogram, "kilogram", "mass", sourceRFC8428),
+		primaryUnit(Gram, "gram", "mass", sourceRFC8428).deprecated(),
+		primaryUnit(Second, "second", "time", sourceRFC8428),
+		primaryUnit(Ampere, "ampere", "electric current", sourceRFC8428),
+		primaryUnit(Kelvin, "kelvin", "temperature", sourceRFC8428),
+		primaryUnit(Candela, "candela", "luminous intensity", sourceRFC8428),
+		primaryUnit(Mole, "mole", "amount of substance", sourceRFC8428),
+		primaryUnit(Hertz, "hertz", "frequency", sourceRFC8428),
+		primaryUnit(Radian, "radian", "angle", sourceRFC8428),
+		primaryUnit(Steradian, "steradian", "solid angle", sourceRFC8428),
+		primaryUnit(Newton, "newton", "force", sourceRFC8428),
+		primaryUnit(Pascal, "pascal", "pressure", sourceRFC8428),
+		primaryUnit(Joule, "joule", "energy", sourceRFC8428),
+		primaryUnit(Watt, "watt", "power", sourceRFC8428),
+		primaryUnit(Coulomb, "coulomb", "electric charge", sourceRFC8428),
+		primaryUnit(Volt, "volt", "voltage", sourceRFC8428),
+		primaryUnit(Farad, "farad", "capacitance", sourceRFC8428),
+		primaryUnit(Ohm, "ohm", "resistance", sourceRFC8428),
+		primaryUnit(Siemens, "siemens", "electrical conductance", sourceRFC8428),
+		primaryUnit(Weber, "weber", "magnetic flux", sourceRFC8428),
+		primaryUnit(Tesla, "tesla", "magnetic flux density", sourceRFC8428),
+		primaryUnit(Henry, "henry", "inductance", sourceRFC8428),
+		primaryUnit(Celsius, "degrees Celsius", "temperature", sourceRFC8428),
+		primaryUnit(Lumen, "lumen", "luminous flux", sourceRFC8428),
+		primaryUnit(Lux, "lux", "illuminance", sourceRFC8428),
+		primaryUnit(Becquerel, "becquerel", "radioactivity", sourceRFC8428),
+		primaryUnit(Gray, "gray", "absorbed dose", sourceRFC8428),
+		primaryUnit(Sievert, "sievert", "dose equivalent", sourceRFC8428),
+		primaryUnit(Katal, "katal", "catalytic activity", sourceRFC8428),
+		primaryUnit(SquareMeter, "square meter", "area", sourceRFC8428),
+		primaryUnit(CubicMeter, "cubic meter", "volume", sourceRFC8428),
+		primaryUnit(Liter, "liter", "volume", sourceRFC8428).deprecated(),
+		primaryUnit(MeterPerSecond, "meter per second", "velocity", sourceRFC8428),
+		primaryUnit(MeterPerSquareSecond, "meter per square second", "acceleration", sourceRFC8428),
+		primaryUnit(CubicMeterPerSecond, "cubic meter per second", "flow rate", sourceRFC8428),
+		primaryUnit(LiterPerSecond, "liter per second", "flow rate", sourceRFC8428).deprecated(),
+		primaryUnit(WattPerSquareMeter, "watt per square meter", "irradiance", sourceRFC8428),
+		primaryUnit(CandelaPerSquareMeter, "candela per square meter", "luminance", sourceRFC8428),
+		primaryUnit(Bit, "bit", "information content", sourceRFC8428),
+		primaryUnit(BitPerSecond, "bit per second", "data rate", sourceRFC8428),
+		primaryUnit(Latitude, "degrees latitude", "latitude", sourceRFC8428),
+		primaryUnit(Longitude, "degrees longitude", "longitude", sourceRFC8428),
+		primaryUnit(PH, "pH value", "acidity", sourceRFC8428).logarithmic(),
+		primaryUnit(Decibel, "decibel", "level", sourceRFC8428).logarithmic(),
+		primaryUnit(DBW, "decibel relative to 1 W", "power level", sourceRFC8428).logarithmic(),
+		primaryUnit(Bel, "bel", "sound pressure level", sourceRFC8428).logarithmic().deprecated(),
+		primaryUnit(Count, "counter value", "count", sourceRFC8428),
+		primaryUnit(Ratio, "ratio", "ratio", sourceRFC8428),
+		primaryUnit(Ratio2, "ratio", "ratio", sourceRFC8428).deprecated(),
+		primaryUnit(RelativeHumidityPercent, "percentage relative humidity", "relative humidity", sourceRFC8428),
+		primaryUnit(RemainingBatteryPercent, "percentage remaining battery energy level", "battery level", sourceRFC8428),
+		primaryUnit(RemainingBatterySeconds, "seconds remaining battery energy level", "battery time", sourceRFC8428),
+		primaryUnit(Rate, "events per second", "event rate", sourceRFC8428),
+		primaryUnit(RPM, "events per minute", "event rate", sourceRFC8428).deprecated(),
+		primaryUnit(HeartRate, "beats per minute", "heart rate", sourceRFC8428).deprecated(),
+		primaryUnit(HeartBeats, "heart beats", "count", sourceRFC8428).deprecated(),
+		primaryUnit(Conductivity, "siemens per meter", "conductivity", sourceRFC8428),
+
+		// RFC8798
+		primaryUnit(Byte, "byte", "information content", sourceRFC8798),
+		primaryUnit(VoltAmpere, "volt-ampere", "apparent power", sourceRFC8798),
+		primaryUnit(VoltAmpereSecond, "volt-ampere second", "apparent energy", sourceRFC8798),
+		primaryUnit(VoltAmpereReactive, "volt-ampere reactive", "reactive power", sourceRFC8798),
+		primaryUnit(VoltAmpereReactiveSecond, "volt-ampere-reactive second", "reactive energy", sourceRFC8798),
+		primaryUnit(JoulePerMeter, "joule per meter", "energy per distance", sourceRFC8798),
+		primaryUnit(KilogramPerCubicMeter, "kilogram per cubic meter", "mass density", sourceRFC8798),
+		primaryUnit(Degree, "degree", "angle", sourceRFC8798).deprecated(),
+
+		// ISO 7027-1:2016
+		primaryUnit(NephelometricTurbidityUnit, "nephelometric turbidity unit", "turbidity", sourceISO7027),
+
+		// Secondary units (RFC8798)
+		secondaryUnit(Millisecond, "millisecond", Second, 1.0/1000, 0, sourceRFC8798),
+		secondaryUnit(Minute, "minute", Second, 60, 0, sourceRFC8798),
+		secondaryUnit(Hour, "hour", Second, 3600, 0, sourceRFC8798),
+		secondaryUnit(Megahertz, "megahertz", Hertz, 1000000, 0, sourceRFC8798),
+		secondaryUnit(Kilowatt, "kilowatt", Watt, 1000, 0, sourceRFC8798),
+		secondaryUnit(KilovoltAmpere, "kilovolt-ampere", VoltAmpere, 1000, 0, sourceRFC8798),
+		secondaryUnit(Kilovar, "kilovar", VoltAmpereReactive, 1000, 0, sourceRFC8798),
+		secondaryUnit(AmpereHour, "ampere-hour", Coulomb, 3600, 0, sourceRFC8798),
+		secondaryUnit(WattHour, "watt-hour", Joule, 3600, 0, sourceRFC8798),
+		secondaryUnit(KilowattHour, "kilowatt-hour", Joule, 3600000, 0, sourceRFC8798),
+		secondaryUnit(VarHour, "var-hour", VoltAmpereReactiveSecond, 3600, 0, sourceRFC8798),
+		secondaryUnit(KilovarHour, "kilovar-hour", VoltAmpereReactiveSecond, 3600000, 0, sourceRFC8798),
+		secondaryUnit(KilovoltAmpereHour, "kilovolt-ampere-hour", VoltAmpereSecond, 3600000, 0, sourceRFC8798),
+		secondaryUnit(WattHourPerKilometer, "watt-hour per kilometer", JoulePerMeter, 3.6, 0, sourceRFC8798),
+		secondaryUnit(Kibibyte, "kibibyte", Byte, 1024, 0, sourceRFC8798),
+		secondaryUnit(Gigabyte, "gigabyte", Byte, 1000000000, 0, sourceRFC8798),
+		secondaryUnit(MegabitPerSecond, "megabit per second", BitPerSecond, 1000000, 0, sourceRFC8798),
+		secondaryUnit(BytePerSecond, "byte per second", BitPerSecond, 8, 0, sourceRFC8798),
+		secondaryUnit(MegabytePerSecond, "megabyte per second", BitPerSecond, 8000000, 0, sourceRFC8798),
+		secondaryUnit(Millivolt, "millivolt", Volt, 1.0/1000, 0, sourceRFC8798),
+		secondaryUnit(Milliampere, "milliampere", Ampere, 1.0/1000, 0, sourceRFC8798),
+		secondaryUnit(DecibelMilliwatt, "decibel relative to 1 mW", DBW, 1, -30, sourceRFC8798),
+		secondaryUnit(MicrogramPerCubicMeter, "microgram per cubic meter", KilogramPerCubicMeter, 1e-9, 0, sourceRFC8798),
+		secondaryUnit(MillimeterPerHour, "millimeter per hour", MeterPerSecond, 1.0/3600000, 0, sourceRFC8798),
+		secondaryUnit(MeterPerHour, "meter per hour", MeterPerSecond, 1.0/3600, 0, sourceRFC8798),
+		secondaryUnit(PartsPerMillion, "parts per million", Ratio, 1e-6, 0, sourceRFC8798),
+		secondaryUnit(Percent, "percent", Ratio, 1.0/100, 0, sourceRFC8798),
+		secondaryUnit(Permille, "permille", Ratio, 1.0/1000, 0, sourceRFC8798),
+		secondaryUnit(Hectopascal, "hectopascal", Pascal, 100, 0, sourceRFC8798),
+		secondaryUnit(Millimeter, "millimeter", Meter, 1.0/1000, 0, sourceRFC8798),
+		secondaryUnit(Centimeter, "centimeter", Meter, 1.0/100, 0, sourceRFC8798),
+		secondaryUnit(Kilometer, "kilometer", Meter, 1000, 0, sourceRFC8798),
+		secondaryUnit(KilometerPerHour, "kilometer per hour", MeterPerSecond, 1/3.6, 0, sourceRFC8798),
+
+		// Secondary units (CoRE-1)
+		secondaryUnit(PartsPerBillion, "parts per billion", Ratio, 1e-9, 0, sourceCoRE1),
+		secondaryUnit(PartsPerTrillion, "parts per trillion", Ratio, 1e-12, 0, sourceCoRE1),
+		secondaryUnit(VoltAmpereHour, "volt-ampere-hour", VoltAmpereSecond, 3600, 0, sourceCoRE1),
+		secondaryUnit(Milligram, "milligram per liter", KilogramPerCubicMeter, 1.0/1000, 0, sourceCoRE1),
+		secondaryUnit(Microgram, "microgram per liter", KilogramPerCubicMeter, 1e-6, 0, sourceCoRE1),
+		secondaryUnit(GramPerLiter, "gram per liter", KilogramPerCubicMeter, 1, 0, sourceCoRE1),
+	} {
+		if i.Secondary() {
+			p := unitRegistry[i.Primary]
+			i.Kind = p.Kind
+			i.Dimension = p.Dimension
+			i.Logarithmic = p.Logarithmic
+		} else {
+			i.Dimension = unitDimensions[i.Symbol]
+		}
+		unitRegistry[i.Symbol] = i
+	}
+}
+
+// RegisterUnit adds a custom unit to the registry.
+// The Primary unit of the info must be a known unit, or the unit itself.
+// A secondary Primary unit is resolved to its own primary unit,
+// combining the Scale and Offset of both units.
+// Secondary units inherit the dimension of their primary unit.
+// A Scale of zero is interpreted as 1.
+// An error is returned if the unit is already known.
+func RegisterUnit(u Unit, info UnitInfo) error {
+	unitRegistryMutex.Lock()
+	defer unitRegistryMutex.Unlock()
+
+	if u == None {
+		return fmt.Errorf("cannot register empty unit")
+	}
+	if _, ok := unitRegistry[u]; ok {
+		return fmt.Errorf("unit %q is already registered", u)
+	}
+
+	info.Symbol = u
+	if info.Primary == None {
+		info.Primary = u
+	}
+	if info.Scale == 0 {
+		info.Scale = 1
+	}
+	if info.Primary != u {
+		p, ok := unitRegistry[info.Primary]
+		if !ok {
+			return fmt.Errorf("primary unit %q of unit %q is unknown", info.Primary, u)
+		}
+		if p.Secondary() {
+			info.Primary = p.Primary
+			info.Offset = info.Offset*p.Scale + p.Offset
+			info.Scale *= p.Scale
+		}
+		if info.Kind == "" {
+			info.Kind = p.Kind
+		}
+		info.Dimension = p.Dimension
+		info.Logarithmic = p.Logarithmic
+	}
+
+	unitRegistry[u] = info
+	return nil
+}
+
+// LookupUnit returns the metadata of the unit with the given symbol.
+// The returned boolean is false if the unit is unknown.
+func LookupUnit(symbol string) (UnitInfo, bool) {
+	unitRegistryMutex.RLock()
+	defer unitRegistryMutex.RUnlock()
+
+	i, ok := unitRegistry[Unit(symbol)]
+	return i, ok
+}
+
+// Units returns all known units, sorted by symbol.
+func Units() []Unit {
+	unitRegistryMutex.RLock()
+	units := make([]Unit, 0, len(unitRegistry))
+	for u := range unitRegistry {
+		units = append(units, u)
+	}
+	unitRegistryMutex.RUnlock()
+
+	sort.Slice(units, func(i, j int) bool {
+		return units[i] < units[j]
+	})
+	return units
+}
+
+// Info returns the metadata of the unit.
+// Only the Symbol, Primary and Scale are set for unknown units.
+func (u Unit) Info() UnitInfo {
+	if i, ok := LookupUnit(string(u)); ok {
+		return i
+	}
+	return UnitInfo{Symbol: u, Primary: u, Scale: 1}
+}
+
+// Known returns true if the unit is empty, standard or registered.
+func (u Unit) Known() bool {
+	if u == None {
+		return true
+	}
+	_, ok := LookupUnit(string(u))
+	return ok
+}
@@ -0,0 +1,148 @@
+package senml
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestUnitInfo(t *testing.T) {
+	tests := map[Unit]UnitInfo{
+		Celsius: {
+			Symbol: "Cel", Description: "degrees Celsius", Kind: "temperature",
+			Source: "RFC8428", Primary: Celsius, Scale: 1, Dimension: Dimension{0, 0, 0, 0, 1, 0, 0},
+		},
+		Gram: {
+			Symbol: "g", Description: "gram", Kind: "mass",
+			Source: "RFC8428", Deprecated: true, Primary: Gram, Scale: 1, Dimension: Dimension{0, 1, 0, 0, 0, 0, 0},
+		},
+		KilowattHour: {
+			Symbol: "kWh", Description: "kilowatt-hour", Kind: "energy",
+			Source: "RFC8798", Primary: Joule, Scale: 3600000, Dimension: Dimension{2, 1, -2, 0, 0, 0, 0},
+		},
+		DecibelMilliwatt: {
+			Symbol: "dBm", Description: "decibel relative to 1 mW", Kind: "power level",
+			Source: "RFC8798", Primary: DBW, Scale: 1, Offset: -30, Logarithmic: true,
+		},
+		Unit("foo"): {Symbol: "foo", Primary: "foo", Scale: 1},
+	}
+
+	for u, exp := range tests {
+		if i := u.Info(); i != exp {
+			t.Errorf("Info for %q incorrect, got:\n%#v\nexpected:\n%#v", u, i, exp)
+		}
+	}
+}
+
+func TestUnits(t *testing.T) {
+	units := Units()
+	if !sort.SliceIsSorted(units, func(i, j int) bool { return units[i] < units[j] }) {
+		t.Errorf("Units are not sorted: %v", units)
+	}
+
+	for _, u := range units {
+		i, ok := LookupUnit(string(u))
+		if !ok {
+			t.Errorf("Unit %q not found", u)
+		}
+		if i.Kind == "" || i.Description == "" || i.Source == "" {
+			t.Errorf("Unit %q has incomplete metadata: %#v", u, i)
+		}
+		if _, ok := LookupUnit(string(i.Primary)); !ok {
+			t.Errorf("Primary unit %q of %q not found", i.Primary, u)
+		}
+	}
+
+	if _, ok := LookupUnit("cel"); ok {
+		t.Errorf("Unexpected unit %q found", "cel")
+	}
+}
+
+// unregisterUnit removes a unit registered in a test from the registry.
+func unregisterUnit(u Unit) {
+	unitRegistryMutex.Lock()
+	defer unitRegistryMutex.Unlock()
+	delete(unitRegistry, u)
+}
+
+func TestRegisterUnit(t *testing.T) {
+	err := RegisterUnit("test-mWh", UnitInfo{Description: "test milliwatt-hour", Source: "test", Primary: Joule, Scale: 3.6})
+	if err != nil {
+		t.Fatalf("Error registering unit: %s", err)
+	}
+	defer unregisterUnit("test-mWh")
+
+	i := Unit("test-mWh").Info()
+	if i.Kind != "energy" || i.Symbol != "test-mWh" {
+		t.Errorf("Registered unit incorrect: %#v", i)
+	}
+
+	v, err := Unit("test-mWh").Convert(1000, WattHour)
+	if err != nil || v != 1 {
+		t.Errorf("Conversion of registered unit incorrect, got %v (%v)", v, err)
+	}
+
+	err = RegisterUnit("test-MWh", UnitInfo{Description: "test megawatt-hour", Source: "test", Primary: WattHour, Scale: 1000000})
+	if err != nil {
+		t.Fatalf("Error registering unit: %s", err)
+	}
+	defer unregisterUnit("test-MWh")
+
+	i = Unit("test-MWh").Info()
+	if i.Primary != Joule || i.Scale != 3600000000 || i.Kind != "energy" {
+		t.Errorf("Registered unit with secondary primary incorrect: %#v", i)
+	}
+
+	v, err = Unit("test-MWh").Convert(1, Joule)
+	if err != nil || v != 3600000000 {
+		t.Errorf("Conversion of registered unit incorrect, got %v (%v)", v, err)
+	}
+
+	errorTests := map[string]struct {
+		Unit Unit
+		Info UnitInfo
+	}{
+		"Empty":           {None, UnitInfo{}},
+		"Duplicate":       {Celsius, UnitInfo{}},
+		"Unknown primary": {"test-foo", UnitInfo{Primary: "test-bar"}},
+	}
+	for n, test := range errorTests {
+		if err := RegisterUnit(test.Unit, test.Info); err == nil {
+			t.Errorf("Expected error for %s", n)
+		}
+	}
+}
+
+func TestDecodeUnknownUnits(t *testing.T) {
+	defer func() { UnknownUnits = AllowUnknownUnits }()
+
+	records := []Record{
+		{BaseUnit: "cel", Name: "a", Value: 1},
+		{Name: "b", Unit: "Cel", Value: 2},
+		{Name: "c", Unit: "foo", Value: 3},
+	}
+	exp := UnknownUnitError{{0, "cel"}, {2, "foo"}}
+
+	UnknownUnits = AllowUnknownUnits
+	if _, err := Decode(records); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	UnknownUnits = FlagUnknownUnits
+	list, err := Decode(records)
+	if len(list) != len(records) {
+		t.Errorf("Expected %v measurements, got %v", len(records), len(list))
+	}
+	if !reflect.DeepEqual(err, exp) {
+		t.Errorf("Expected error %v, got %v", exp, err)
+	}
+
+	UnknownUnits = RejectUnknownUnits
+	list, err = Decode(records)
+	if list != nil {
+		t.Errorf("Expected no measurements, got %v", list)
+	}
+	if !reflect.DeepEqual(err, exp[:1]) {
+		t.Errorf("Expected error %v, got %v", exp[:1], err)
+	}
+}
@@ -0,0 +1,313 @@
+package senml
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+var measurementType = reflect.TypeOf((*Measurement)(nil)).Elem()
+
+// UnmarshalResult contains the measurements and fields that were not matched by Unmarshal.
+type UnmarshalResult struct {
+	// Unmatched contains the measurements that did not match any field.
+	Unmatched []Measurement
+
+	// Missing contains the names of the fields for which no measurement was found.
+	Missing []string
+}
+
+// unmarshaller contains the state of Unmarshal.
+type unmarshaller struct {
+	names    map[string][]Measurement
+	used     map[string]bool
+	assigned int
+	path     map[reflect.Type]bool
+	result   UnmarshalResult
+}
+
+// Unmarshal assigns measurements to the fields of the struct pointed to by v.
+// Fields are matched by their resolved names as described for Marshal,
+// without a base name. UnmarshalWithBaseName can be used for measurements
+// that were marshalled with a base name.
+//
+// Numeric fields accept Value and Sum measurements, bool fields accept Boolean,
+// string fields accept String and []byte fields accept Data measurements.
+// Numeric values are converted to the unit in the struct tag if it differs from
+// the unit of the measurement. A time.Duration field is assigned from a value
+// in any unit of time, and a Quantity field is assigned the value and unit.
+//
+// Slice fields receive all measurements with the matching name in order of time.
+// Slices of Measurement or of a Measurement type (eg: []*Value) can be used
+// to retain the timestamps of the values.
+// Other fields are assigned the most recent measurement with the matching name.
+// Nil pointers to nested structs are only allocated if any of their fields is assigned.
+// Nested structs of a type that is already being unmarshalled are skipped,
+// as recursive types would otherwise be unmarshalled endlessly.
+// Fields of type time.Time are not supported.
+//
+// The measurements and fields that were not matched are returned in the result.
+func Unmarshal(list []Measurement, v interface{}) (*UnmarshalResult, error) {
+	return UnmarshalWithBaseName(list, "", v)
+}
+
+// UnmarshalWithBaseName is like Unmarshal, but matches the fields by their
+// resolved names using the given base name, as returned by Marshal.
+func UnmarshalWithBaseName(list []Measurement, baseName string, v interface{}) (*UnmarshalResult, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot unmarshal into %T: not a pointer to a struct", v)
+	}
+
+	u := &unmarshaller{
+		names: make(map[string][]Measurement),
+		used:  make(map[string]bool),
+		path:  make(map[reflect.Type]bool),
+	}
+	for _, m := range list {
+		name := m.Attrs().Name
+		u.names[name] = append(u.names[name], m)
+	}
+	for _, ml := range u.names {
+		sort.SliceStable(ml, func(i, j int) bool {
+			return ml[i].Attrs().Time.Before(ml[j].Attrs().Time)
+		})
+	}
+
+	if err := u.unmarshalStruct(rv.Elem(), baseName); err != nil {
+		return nil, err
+	}
+
+	for _, m := range list {
+		if !u.used[m.Attrs().Name] {
+			u.result.Unmatched = append(u.result.Unmatched, m)
+		}
+	}
+
+	return &u.result, nil
+}
+
+// unmarshalStruct assigns measurements to the fields of a struct.
+// Nothing is assigned if the struct is nested in a struct of the same type.
+func (u *unmarshaller) unmarshalStruct(rv reflect.Value, prefix string) error {
+	rt := rv.Type()
+	if u.path[rt] {
+		return nil
+	}
+	u.path[rt] = true
+	defer delete(u.path, rt)
+
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+
+		tag, err := parseFieldTag(f)
+		if err != nil {
+			return err
+		}
+		if tag.Skip {
+			continue
+		}
+
+		ft := indirectType(f.Type)
+		if ft == timeType {
+			return fmt.Errorf("field %s: unsupported type %s", f.Name, f.Type)
+		}
+
+		if ft.Kind() == reflect.Struct && ft != quantityType && !isMeasurementType(ft) {
+			name := prefix + tag.Name + nameSeparator
+			if _, ok := f.Tag.Lookup("senml"); f.Anonymous && !ok {
+				name = prefix
+			}
+			if err := u.unmarshalNested(rv.Field(i), ft, name); err != nil {
+				return err
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name := prefix + tag.Name
+		ml, ok := u.names[name]
+		if !ok {
+			u.result.Missing = append(u.result.Missing, name)
+			continue
+		}
+		u.used[name] = true
+		u.assigned++
+
+		if err := unmarshalField(rv.Field(i), tag, ml); err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// unmarshalNested assigns measurements to the fields of a nested struct of type ft.
+// A nil pointer to the struct is only set if any of its fields is assigned.
+func (u *unmarshaller) unmarshalNested(fv reflect.Value, ft reflect.Type, prefix string) error {
+	if fv.Kind() != reflect.Ptr {
+		return u.unmarshalStruct(fv, prefix)
+	}
+	if !fv.CanSet() {
+		return nil
+	}
+	if !fv.IsNil() {
+		return u.unmarshalStruct(fv.Elem(), prefix)
+	}
+
+	assigned := u.assigned
+	nv := reflect.New(ft)
+	if err := u.unmarshalStruct(nv.Elem(), prefix); err != nil {
+		return err
+	}
+	if u.assigned > assigned {
+		fv.Set(nv)
+	}
+	return nil
+}
+
+// unmarshalField assigns a list of measurements to a field.
+func unmarshalField(fv reflect.Value, tag fieldTag, ml []Measurement) error {
+	for fv.Kind() == reflect.Ptr && !fv.Type().Implements(measurementType) {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		fv = fv.Elem()
+	}
+
+	if fv.Kind() != reflect.Slice || fv.Type() == bytesType {
+		return unmarshalValue(fv, tag, ml[len(ml)-1])
+	}
+
+	s := reflect.MakeSlice(fv.Type(), len(ml), len(ml))
+	for i, m := range ml {
+		if err := unmarshalValue(s.Index(i), tag, m); err != nil {
+			return err
+		}
+	}
+	fv.Set(s)
+	return nil
+}
+
+// unmarshalValue assigns a measurement to a value.
+func unmarshalValue(fv reflect.Value, tag fieldTag, m Measurement) error {
+	mv := reflect.ValueOf(m)
+	switch {
+	case fv.Type() == measurementType:
+		fv.Set(mv)
+		return nil
+	case fv.Type().Implements(measurementType):
+		if !mv.Type().AssignableTo(fv.Type()) {
+			return fmt.Errorf("cannot assign %T to %s", m, fv.Type())
+		}
+		fv.Set(mv)
+		return nil
+	case isMeasurementType(fv.Type()):
+		if mv.Type() != reflect.PtrTo(fv.Type()) {
+			return fmt.Errorf("cannot assign %T to %s", m, fv.Type())
+		}
+		fv.Set(mv.Elem())
+		return nil
+	}
+
+	switch v := m.(type) {
+	case *Value:
+		if tag.Kind != "" && tag.Kind != kindValue {
+			return fmt.Errorf("cannot assign %T to field with kind %q", m, tag.Kind)
+		}
+		return unmarshalNumeric(fv, tag, v.Value, v.Unit)
+	case *Sum:
+		if tag.Kind != "" && tag.Kind != kindSum {
+			return fmt.Errorf("cannot assign %T to field with kind %q", m, tag.Kind)
+		}
+		return unmarshalNumeric(fv, tag, v.Value, v.Unit)
+	case *Boolean:
+		if fv.Kind() != reflect.Bool {
+			return fmt.Errorf("cannot assign %T to %s", m, fv.Type())
+		}
+		fv.SetBool(v.Value)
+	case *String:
+		if fv.Kind() != reflect.String {
+			return fmt.Errorf("cannot assign %T to %s", m, fv.Type())
+		}
+		fv.SetString(v.Value)
+	case *ObjectLink:
+		if fv.Kind() != reflect.String {
+			return fmt.Errorf("cannot assign %T to %s", m, fv.Type())
+		}
+		fv.SetString(v.Value)
+	case *Data:
+		switch {
+		case fv.Type() == bytesType:
+			fv.SetBytes(v.Value)
+		case fv.Kind() == reflect.String && tag.Kind == kindData:
+			fv.SetString(string(v.Value))
+		default:
+			return fmt.Errorf("cannot assign %T to %s", m, fv.Type())
+		}
+	default:
+		return fmt.Errorf("unsupported measurement type %T", m)
+	}
+
+	return nil
+}
+
+// unmarshalNumeric assigns a numeric value to a value, converting it to the unit in the tag.
+func unmarshalNumeric(fv reflect.Value, tag fieldTag, f float64, unit Unit) (err error) {
+	switch fv.Type() {
+	case durationType:
+		if unit == None {
+			unit = Second
+		}
+		if f, err = unit.Convert(f, Second); err != nil {
+			return err
+		}
+		fv.SetInt(int64(floatToDuration(f)))
+		return nil
+	case quantityType:
+		q := NewQuantity(f, unit)
+		if tag.Unit != None && unit != None {
+			if q, err = q.Convert(tag.Unit); err != nil {
+				return err
+			}
+		}
+		fv.Set(reflect.ValueOf(q))
+		return nil
+	}
+
+	if tag.Unit != None && unit != None {
+		if f, err = unit.Convert(f, tag.Unit); err != nil {
+			return err
+		}
+	}
+
+	// Correct rounding errors introduced by unit conversion
+	if r := math.Round(f); math.Abs(f-r) <= 1e-9*math.Abs(r) {
+		f = r
+	}
+
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f != math.Trunc(f) || fv.OverflowInt(int64(f)) {
+			return fmt.Errorf("cannot assign %v to %s", f, fv.Type())
+		}
+		fv.SetInt(int64(f))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if f != math.Trunc(f) || f < 0 || fv.OverflowUint(uint64(f)) {
+			return fmt.Errorf("cannot assign %v to %s", f, fv.Type())
+		}
+		fv.SetUint(uint64(f))
+	case reflect.Float32, reflect.Float64:
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot assign numeric value to %s", fv.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package senml
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testReadings struct {
+	Temperature float64       `senml:"temperature,unit=K"`
+	Energy      uint64        `senml:"energy,unit=Wh,kind=sum"`
+	Open        *bool         `senml:"open"`
+	Label       string        `senml:"label"`
+	Payload     []byte        `senml:"payload"`
+	Uptime      time.Duration `senml:"uptime"`
+	Power       Quantity      `senml:"power,unit=W"`
+	Current     []float64     `senml:"current,unit=mA"`
+	Voltage     []*Value      `senml:"voltage"`
+	Location    testLocation  `senml:"location"`
+	Missing     int           `senml:"missing"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	t0 := time.Unix(1600000000, 0)
+	list := []Measurement{
+		NewValue("dev:temperature", 20, Celsius, t0, 0),
+		NewSum("dev:energy", 1.5, KilowattHour, t0, 0),
+		NewBoolean("dev:open", true, None, t0, 0),
+		NewString("dev:label", "Machine Room", None, t0, 0),
+		NewData("dev:payload", []byte{1, 2}, None, t0, 0),
+		NewValue("dev:uptime", 1.5, Minute, t0, 0),
+		NewValue("dev:power", 2, Kilowatt, t0, 0),
+		NewValue("dev:current", 1.2, Ampere, t0.Add(time.Second), 0),
+		NewValue("dev:current", 1.1, Ampere, t0, 0),
+		NewValue("dev:voltage", 230, Volt, t0, 0),
+		NewValue("dev:voltage", 231, Volt, t0.Add(time.Second), 0),
+		NewValue("dev:location:lat", 52.1, Latitude, t0, 0),
+		NewValue("dev:location:lon", 5.1, Longitude, t0, 0),
+		NewValue("dev:other", 1, None, t0, 0),
+	}
+
+	var r testReadings
+	res, err := UnmarshalWithBaseName(list, "dev:", &r)
+	if err != nil {
+		t.Fatalf("Error unmarshalling: %s", err)
+	}
+
+	open := true
+	exp := testReadings{
+		Temperature: 293.15,
+		Energy:      1500,
+		Open:        &open,
+		Label:       "Machine Room",
+		Payload:     []byte{1, 2},
+		Uptime:      90 * time.Second,
+		Power:       NewQuantity(2000, Watt),
+		Current:     []float64{1100, 1200},
+		Voltage:     []*Value{list[9].(*Value), list[10].(*Value)},
+		Location:    testLocation{Latitude: 52.1, Longitude: 5.1},
+	}
+	if !reflect.DeepEqual(r, exp) {
+		t.Errorf("Unmarshal incorrect, got:\n%#v\nexpected:\n%#v", r, exp)
+	}
+
+	expRes := &UnmarshalResult{
+		Unmatched: list[13:],
+		Missing:   []string{"dev:missing"},
+	}
+	if !reflect.DeepEqual(res, expRes) {
+		t.Errorf("Unmarshal result incorrect, got:\n%#v\nexpected:\n%#v", res, expRes)
+	}
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	dev := testDevice{
+		testCommon:  testCommon{Label: "Machine Room"},
+		Temperature: 23.5,
+		Energy:      1200,
+		Sensor:      "3303:0",
+		Uptime:      time.Minute,
+		Power:       NewQuantity(1500, Watt),
+		Location:    testLocation{Latitude: 52.1, Longitude: 5.1},
+	}
+
+	list, err := Marshal(dev, "dev:", now)
+	if err != nil {
+		t.Fatalf("Error marshalling: %s", err)
+	}
+
+	var res testDevice
+	if _, err := UnmarshalWithBaseName(list, "dev:", &res); err != nil {
+		t.Fatalf("Error unmarshalling: %s", err)
+	}
+	if !reflect.DeepEqual(res, dev) {
+		t.Errorf("Unmarshal incorrect, got:\n%#v\nexpected:\n%#v", res, dev)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		List []Measurement
+		V    interface{}
+	}{
+		"Not a pointer": {nil, struct{}{}},
+		"Time": {nil, &struct {
+			T time.Time `senml:"t"`
+		}{}},
+		"Type mismatch": {
+			[]Measurement{NewString("a", "1", None, now, 0)},
+			&struct {
+				A float64 `senml:"a"`
+			}{},
+		},
+		"Incompatible unit": {
+			[]Measurement{NewValue("a", 1, Meter, now, 0)},
+			&struct {
+				A float64 `senml:"a,unit=s"`
+			}{},
+		},
+		"Kind mismatch": {
+			[]Measurement{NewValue("a", 1, None, now, 0)},
+			&struct {
+				A float64 `senml:"a,kind=sum"`
+			}{},
+		},
+		"Fractional integer": {
+			[]Measurement{NewValue("a", 1.5, None, now, 0)},
+			&struct {
+				A int `senml:"a"`
+			}{},
+		},
+		"Overflow": {
+			[]Measurement{NewValue("a", 300, None, now, 0)},
+			&struct {
+				A uint8 `senml:"a"`
+			}{},
+		},
+		"Measurement type mismatch": {
+			[]Measurement{NewSum("a", 1, None, now, 0)},
+			&struct {
+				A []*Value `senml:"a"`
+			}{},
+		},
+	}
+
+	for n, test := range tests {
+		if _, err := Unmarshal(test.List, test.V); err == nil {
+			t.Errorf("Expected error for %s", n)
+		}
+	}
+}
+
+func TestUnmarshalNilPointers(t *testing.T) {
+	type nested struct {
+		Location *testLocation `senml:"location"`
+		Position *testLocation `senml:"position"`
+	}
+
+	list := []Measurement{NewValue("location:lat", 52.1, Latitude, time.Unix(1600000000, 0), 0)}
+
+	var r nested
+	if _, err := Unmarshal(list, &r); err != nil {
+		t.Fatalf("Error unmarshalling: %s", err)
+	}
+	if r.Location == nil || r.Location.Latitude != 52.1 {
+		t.Errorf("Expected location to be set, got %#v", r.Location)
+	}
+	if r.Position != nil {
+		t.Errorf("Expected position to be nil, got %#v", r.Position)
+	}
+}
+
+func TestUnmarshalMeasurementFields(t *testing.T) {
+	temperature := NewValue("temperature", 23.5, Celsius, time.Unix(1600000000, 0), 0)
+	state := NewString("state", "ok", None, time.Unix(1600000000, 0), 0)
+
+	var r struct {
+		Temperature *Value      `senml:"temperature"`
+		State       Measurement `senml:"state"`
+		Copy        Value       `senml:"temperature"`
+	}
+	res, err := Unmarshal([]Measurement{temperature, state}, &r)
+	if err != nil {
+		t.Fatalf("Error unmarshalling: %s", err)
+	}
+	if r.Temperature != temperature || r.State != state || !r.Copy.Equal(temperature) {
+		t.Errorf("Measurement fields incorrect, got %#v", r)
+	}
+	if len(res.Missing) != 0 || len(res.Unmatched) != 0 {
+		t.Errorf("Unexpected result: %#v", res)
+	}
+}
+
+func TestUnmarshalRecursive(t *testing.T) {
+	type node struct {
+		V    float64 `senml:"v"`
+		Next *node   `senml:"next"`
+	}
+
+	list := []Measurement{
+		NewValue("v", 1, None, time.Unix(1600000000, 0), 0),
+		NewValue("next:v", 2, None, time.Unix(1600000000, 0), 0),
+	}
+
+	var r node
+	res, err := Unmarshal(list, &r)
+	if err != nil {
+		t.Fatalf("Error unmarshalling: %s", err)
+	}
+	if r.V != 1 || r.Next != nil {
+		t.Errorf("Recursive struct incorrect, got %#v", r)
+	}
+	if len(res.Unmatched) != 1 || res.Unmatched[0] != list[1] {
+		t.Errorf("Unmatched measurements incorrect, got %v", res.Unmatched)
+	}
+}
@@ -42,10 +42,11 @@ func lcp(l []string) string {
 }
 
 // maxUnit returns the unit with the greatest value from a map.
-func maxUnit(units map[Unit]int) (unit Unit) {
+// Ties are broken by the first appearance of the unit in order.
+func maxUnit(units map[Unit]int, order []Unit) (unit Unit) {
 	maxV := 1
-	for u, c := range units {
-		if c > maxV {
+	for _, u := range order {
+		if c := units[u]; c > maxV {
 			unit = u
 			maxV = c
 		}
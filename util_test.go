@@ -3,6 +3,8 @@ package senml
 import (
 	"fmt"
 	"strings"
+	"testing"
+	"time"
 )
 
 func toString(ml []Measurement) string {
@@ -15,6 +17,9 @@ func toString(ml []Measurement) string {
 }
 
 func equal(a, b []Measurement) bool {
+	if len(a) != len(b) {
+		return false
+	}
 	for i := range a {
 		if !a[i].Equal(b[i]) {
 			return false
@@ -22,3 +27,10 @@ func equal(a, b []Measurement) bool {
 	}
 	return true
 }
+
+func TestEqualLength(t *testing.T) {
+	list := []Measurement{NewValue("a", 1, None, time.Now(), 0)}
+	if equal(nil, list) || equal(list, nil) {
+		t.Errorf("Lists of different length are equal")
+	}
+}
@@ -0,0 +1,134 @@
+package senml
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// RecordError represents an error in a single record of a pack.
+type RecordError struct {
+	Record int
+	Err    error
+}
+
+// Error returns the error message.
+func (e RecordError) Error() string {
+	return fmt.Sprintf("record %d: %s", e.Record, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e RecordError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is returned by Validate when records do not conform to RFC 8428.
+type ValidationError []RecordError
+
+// Error returns the error message.
+func (e ValidationError) Error() string {
+	strs := make([]string, len(e))
+	for i, r := range e {
+		strs[i] = r.Error()
+	}
+	return strings.Join(strs, ", ")
+}
+
+// Validate checks a list of records against the rules of RFC 8428.
+// The following is checked for each record:
+//
+//   - the resolved name is not empty and only contains valid characters,
+//   - the record contains a value or a sum, and at most one value,
+//   - numeric fields are finite numbers, and the update time is not negative,
+//   - the base version does not differ from the other records,
+//   - the units are known, see Unit.Known.
+//
+// A ValidationError containing all errors is returned if any rule is violated.
+func Validate(records []Record) error {
+	var errs ValidationError
+	var baseName string
+	var baseVersion int
+
+	fail := func(i int, format string, a ...interface{}) {
+		errs = append(errs, RecordError{Record: i, Err: fmt.Errorf(format, a...)})
+	}
+
+	for i, r := range records {
+		if r.BaseName != "" {
+			baseName = r.BaseName
+		}
+
+		if r.BaseVersion != 0 {
+			if baseVersion != 0 && r.BaseVersion != baseVersion {
+				fail(i, "base version %d differs from %d", r.BaseVersion, baseVersion)
+			}
+			baseVersion = r.BaseVersion
+		}
+
+		if err := validateName(baseName + r.Name); err != nil {
+			fail(i, "%s", err)
+		}
+
+		values := 0
+		for _, ok := range []bool{r.Value != nil, r.StringValue != "", r.BooleanValue != nil, len(r.DataValue) > 0, r.ObjectLink != ""} {
+			if ok {
+				values++
+			}
+		}
+		switch {
+		case values > 1:
+			fail(i, "record contains %d values", values)
+		case values == 0 && r.Sum == nil:
+			fail(i, "record contains no value or sum")
+		}
+
+		for _, n := range []struct {
+			Field string
+			Value Numeric
+		}{
+			{"bt", r.BaseTime}, {"bv", r.BaseValue}, {"bs", r.BaseSum},
+			{"v", r.Value}, {"s", r.Sum}, {"t", r.Time}, {"ut", r.UpdateTime},
+		} {
+			switch n.Value.(type) {
+			case nil:
+			case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, Decimal, *Decimal:
+				if f := numericToFloat64(n.Value); math.IsNaN(f) || math.IsInf(f, 0) {
+					fail(i, "field %s is not a finite number", n.Field)
+				} else if n.Field == "ut" && f < 0 {
+					fail(i, "field %s is negative", n.Field)
+				}
+			default:
+				fail(i, "field %s is not a number", n.Field)
+			}
+		}
+
+		for _, u := range []string{r.BaseUnit, r.Unit} {
+			if u := decodeUnit(u); !u.Known() {
+				fail(i, "unknown unit %q", u)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateName checks if a resolved name conforms to section 4.5.1 of RFC 8428.
+func validateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name is empty")
+	}
+
+	for i, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case i > 0 && strings.ContainsRune("-:./_", c):
+		default:
+			return fmt.Errorf("name %q contains invalid character %q at position %d", name, c, i)
+		}
+	}
+
+	return nil
+}
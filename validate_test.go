@@ -0,0 +1,81 @@
+package senml
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	valid := true
+	tests := map[string]struct {
+		Records []Record
+		Errors  []int
+	}{
+		"Valid": {
+			Records: []Record{
+				{BaseName: "urn:dev:ow:10e2073a01080063:", BaseVersion: 10, Name: "voltage", Unit: "V", Value: 120.1},
+				{Name: "current", Unit: "A", Sum: 1.2, Time: -5},
+				{Name: "open", BooleanValue: &valid, BaseVersion: 10},
+			},
+		},
+		"Invalid name": {
+			Records: []Record{
+				{Name: "temp", Value: 1},
+				{Name: "-temp", Value: 1},
+				{Name: "temp erature", Value: 1},
+				{Value: 1},
+			},
+			Errors: []int{1, 2, 3},
+		},
+		"Values": {
+			Records: []Record{
+				{Name: "a"},
+				{Name: "b", Value: 1, StringValue: "1"},
+				{Name: "c", Value: math.NaN()},
+				{Name: "d", Value: "1"},
+				{Name: "e", Value: 1, UpdateTime: -1},
+			},
+			Errors: []int{0, 1, 2, 3, 4},
+		},
+		"Base version": {
+			Records: []Record{
+				{BaseVersion: 10, Name: "a", Value: 1},
+				{BaseVersion: 5, Name: "b", Value: 1},
+			},
+			Errors: []int{1},
+		},
+		"Unknown unit": {
+			Records: []Record{
+				{BaseUnit: "cel", Name: "a", Value: 1},
+				{Name: "b", Unit: "Cel", Value: 1},
+			},
+			Errors: []int{0},
+		},
+	}
+
+	for n, test := range tests {
+		err := Validate(test.Records)
+		if len(test.Errors) == 0 {
+			if err != nil {
+				t.Errorf("Unexpected error for %s: %s", n, err)
+			}
+			continue
+		}
+
+		var verr ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("Expected validation error for %s, got: %v", n, err)
+			continue
+		}
+
+		records := make([]int, len(verr))
+		for i, e := range verr {
+			records[i] = e.Record
+		}
+		if !reflect.DeepEqual(records, test.Errors) {
+			t.Errorf("Errors for %s incorrect, got records %v, expected %v: %s", n, records, test.Errors, err)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package senml
 
 import (
 	"encoding/xml"
+	"fmt"
+	"strconv"
 )
 
 const (
@@ -13,21 +15,88 @@ const (
 type xmlContainer struct {
 	XMLName      xml.Name `xml:"sensml" name:"urn:ietf:params:xml:ns:senml"`
 	XMLNamespace string   `xml:"xmlns,attr"`
-	Objs         []Record
+	Objs         []Record `xml:"senml"`
 }
 
-// EncodeXML encodes a list of measurements into XML.
-func EncodeXML(list []Measurement) (b []byte, err error) {
-	c := xmlContainer{Objs: Encode(list), XMLNamespace: xmlNamespace}
-	return xml.Marshal(c)
+// xmlDecodeContainer is used for decoding XML, as Numeric attributes cannot be decoded directly.
+type xmlDecodeContainer struct {
+	XMLName xml.Name    `xml:"sensml"`
+	Objs    []xmlRecord `xml:"senml"`
+}
+
+// xmlRecord represents a Record with Numeric attributes as strings.
+type xmlRecord struct {
+	Record
+	BaseTime   xmlNumeric `xml:"bt,attr"`
+	BaseValue  xmlNumeric `xml:"bv,attr"`
+	BaseSum    xmlNumeric `xml:"bs,attr"`
+	Value      xmlNumeric `xml:"v,attr"`
+	Sum        xmlNumeric `xml:"s,attr"`
+	Time       xmlNumeric `xml:"t,attr"`
+	UpdateTime xmlNumeric `xml:"ut,attr"`
+}
+
+// xmlNumeric represents a Numeric XML attribute.
+type xmlNumeric struct {
+	Numeric
+}
+
+// UnmarshalXMLAttr decodes a Numeric value from an XML attribute.
+// Integers are decoded as int64, other values as float64.
+func (n *xmlNumeric) UnmarshalXMLAttr(attr xml.Attr) error {
+	if i, err := strconv.ParseInt(attr.Value, 10, 64); err == nil {
+		n.Numeric = i
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(attr.Value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid numeric value for attribute %s: %q", attr.Name.Local, attr.Value)
+	}
+	n.Numeric = f
+	return nil
 }
 
-// DecodeXML decodes a list of measurements from XML.
+// record returns the Record with the decoded Numeric attributes.
+func (r xmlRecord) record() Record {
+	rec := r.Record
+	rec.BaseTime = r.BaseTime.Numeric
+	rec.BaseValue = r.BaseValue.Numeric
+	rec.BaseSum = r.BaseSum.Numeric
+	rec.Value = r.Value.Numeric
+	rec.Sum = r.Sum.Numeric
+	rec.Time = r.Time.Numeric
+	rec.UpdateTime = r.UpdateTime.Numeric
+	return rec
+}
+
+// EncodeXML encodes a list of measurements into XML using the registered "xml" codec.
+func EncodeXML(list []Measurement) ([]byte, error) {
+	return encodeCodec("xml", list)
+}
+
+// DecodeXML decodes a list of measurements from XML using the registered "xml" codec.
 func DecodeXML(x []byte) ([]Measurement, error) {
-	c := new(xmlContainer)
+	return decodeCodec("xml", x)
+}
+
+// EncodeXMLRecords encodes a list of records into XML.
+func EncodeXMLRecords(records []Record) ([]byte, error) {
+	c := xmlContainer{Objs: records, XMLNamespace: xmlNamespace}
+	return xml.Marshal(c)
+}
+
+// DecodeXMLRecords decodes a list of records from XML.
+func DecodeXMLRecords(x []byte) ([]Record, error) {
+	c := new(xmlDecodeContainer)
 	err := xml.Unmarshal(x, c)
 	if err != nil {
 		return nil, err
 	}
-	return Decode(c.Objs)
+
+	records := make([]Record, len(c.Objs))
+	for i, r := range c.Objs {
+		records[i] = r.record()
+	}
+	return records, nil
 }
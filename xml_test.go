@@ -32,6 +32,33 @@ func TestDecodeXMLExamples(t *testing.T) {
 	}
 }
 
+// TestDecodeXMLRecords ensures that all records and their numeric attributes are decoded,
+// as DecodeXML previously returned an empty list.
+func TestDecodeXMLRecords(t *testing.T) {
+	x := `<sensml xmlns="urn:ietf:params:xml:ns:senml">` +
+		`<senml bn="urn:dev:ow:10e2073a01080063:" bt="1320067464" n="voltage" u="V" v="120.1"></senml>` +
+		`<senml n="current" t="-5" u="A" v="1"></senml>` +
+		`</sensml>`
+
+	records, err := DecodeXMLRecords([]byte(x))
+	if err != nil {
+		t.Fatalf("Error decoding: %s", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %v", len(records))
+	}
+	if records[0].BaseTime != int64(1320067464) || records[0].Value != 120.1 {
+		t.Errorf("Numeric attributes of record 0 incorrect: %#v", records[0])
+	}
+	if records[1].Time != int64(-5) || records[1].Value != int64(1) {
+		t.Errorf("Numeric attributes of record 1 incorrect: %#v", records[1])
+	}
+
+	if _, err := DecodeXMLRecords([]byte(`<sensml><senml n="a" v="a"></senml></sensml>`)); err == nil {
+		t.Errorf("Expected error for invalid numeric attribute")
+	}
+}
+
 func BenchmarkEncodeXML(b *testing.B) {
 	v := "Multiple Measurements"
 	ms := testVectors[v].Result